@@ -0,0 +1,62 @@
+// Command xerrordoc generates the catalog of the xerror codes declared in Go packages as Markdown or OpenAPI components.
+// Codes are collected from ErrCode* string constants, xerror.CodeInfo literals and xerror.Define calls
+//
+//	go run github.com/kurzgesagtz/xgo/cmd/xerrordoc -format markdown -o docs/errors.md ./...
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"os"
+)
+
+func main() {
+	format := flag.String("format", "markdown", "output format: markdown, openapi or openapi-json")
+	output := flag.String("o", "", "output file, default is stdout")
+	appName := flag.String("app", "my-service", "app name used in example payloads")
+	flag.Parse()
+
+	patterns := flag.Args()
+	if len(patterns) == 0 {
+		patterns = []string{"./..."}
+	}
+	if err := run(*format, *output, *appName, patterns); err != nil {
+		fmt.Fprintln(os.Stderr, "xerrordoc:", err)
+		os.Exit(1)
+	}
+}
+
+func run(format, output, appName string, patterns []string) error {
+	render, err := renderer(format)
+	if err != nil {
+		return err
+	}
+	codes, err := Scan(patterns...)
+	if err != nil {
+		return err
+	}
+	var w io.Writer = os.Stdout
+	if output != "" {
+		f, err := os.Create(output)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		w = f
+	}
+	return render(w, codes, appName)
+}
+
+func renderer(format string) (func(w io.Writer, codes []Code, appName string) error, error) {
+	switch format {
+	case "markdown", "md":
+		return RenderMarkdown, nil
+	case "openapi", "openapi-yaml":
+		return RenderOpenAPIYAML, nil
+	case "openapi-json":
+		return RenderOpenAPIJSON, nil
+	default:
+		return nil, fmt.Errorf("unsupported format %q", format)
+	}
+}
@@ -0,0 +1,186 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+const exampleTimestamp = 1704067200000
+
+// example is the JSON body rendered by the gin ErrorHandler for a code
+type example struct {
+	Code      string         `json:"code" yaml:"code"`
+	Message   string         `json:"message" yaml:"message"`
+	AppName   string         `json:"app_name,omitempty" yaml:"app_name,omitempty"`
+	Info      map[string]any `json:"info,omitempty" yaml:"info,omitempty"`
+	Timestamp int64          `json:"timestamp" yaml:"timestamp"`
+}
+
+func newExample(c Code, appName string) example {
+	ex := example{
+		Code:      c.Code,
+		Message:   c.Message,
+		AppName:   appName,
+		Timestamp: exampleTimestamp,
+	}
+	if len(c.Definitions) > 0 {
+		d := c.Definitions[0]
+		if d.Message != "" {
+			ex.Message = d.Message
+		}
+		for _, k := range d.InfoKeys {
+			if ex.Info == nil {
+				ex.Info = make(map[string]any)
+			}
+			ex.Info[k] = "string"
+		}
+	}
+	if ex.Message == "" {
+		ex.Message = c.Code
+	}
+	return ex
+}
+
+// RenderMarkdown writes a summary table followed by a section per code
+func RenderMarkdown(w io.Writer, codes []Code, appName string) error {
+	var b strings.Builder
+	b.WriteString("# Error codes\n\n")
+	b.WriteString("| Code | HTTP status | gRPC code | Retryable | Description |\n")
+	b.WriteString("|------|-------------|-----------|-----------|-------------|\n")
+	for _, c := range codes {
+		fmt.Fprintf(&b, "| [`%s`](#%s) | %d | %s | %s | %s |\n",
+			c.Code, anchor(c.Code), c.HTTPStatus, c.GRPCCode, yesNo(c.Retryable), escapeCell(c.Description))
+	}
+
+	for _, c := range codes {
+		fmt.Fprintf(&b, "\n## %s\n\n", c.Code)
+		if c.Description != "" {
+			fmt.Fprintf(&b, "%s\n\n", c.Description)
+		}
+		if c.Const != "" {
+			fmt.Fprintf(&b, "- Constant: `%s.%s`\n", c.Package, c.Const)
+		}
+		fmt.Fprintf(&b, "- HTTP status: %d %s\n", c.HTTPStatus, http.StatusText(c.HTTPStatus))
+		fmt.Fprintf(&b, "- gRPC code: %s\n", c.GRPCCode)
+		fmt.Fprintf(&b, "- Retryable: %s\n", yesNo(c.Retryable))
+		fmt.Fprintf(&b, "- Log level: %s\n", c.LogLevel)
+		if !c.Registered {
+			b.WriteString("- Not registered, the metadata of INTERNAL_ERROR applies\n")
+		}
+		if len(c.Definitions) > 0 {
+			b.WriteString("\n| Definition | Message | Info keys |\n")
+			b.WriteString("|------------|---------|-----------|\n")
+			for _, d := range c.Definitions {
+				fmt.Fprintf(&b, "| `%s.%s` | %s | %s |\n", d.Package, d.Name, escapeCell(d.Message), strings.Join(d.InfoKeys, ", "))
+			}
+		}
+		payload, err := json.MarshalIndent(newExample(c, appName), "", "  ")
+		if err != nil {
+			return err
+		}
+		fmt.Fprintf(&b, "\n```json\n%s\n```\n", payload)
+	}
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+// OpenAPI builds an OpenAPI 3 document with the Error schema and an example and a response per code
+func OpenAPI(codes []Code, appName string) map[string]any {
+	enum := make([]string, 0, len(codes))
+	examples := make(map[string]any, len(codes))
+	responses := make(map[string]any, len(codes))
+	for _, c := range codes {
+		enum = append(enum, c.Code)
+		examples[c.Code] = map[string]any{
+			"summary": c.Description,
+			"value":   newExample(c, appName),
+		}
+		responses[c.Code] = map[string]any{
+			"description": fmt.Sprintf("%d %s: %s", c.HTTPStatus, http.StatusText(c.HTTPStatus), c.Description),
+			"content": map[string]any{
+				"application/json": map[string]any{
+					"schema": map[string]any{"$ref": "#/components/schemas/Error"},
+					"examples": map[string]any{
+						c.Code: map[string]any{"$ref": "#/components/examples/" + c.Code},
+					},
+				},
+			},
+			"x-http-status": c.HTTPStatus,
+			"x-grpc-code":   c.GRPCCode,
+			"x-retryable":   c.Retryable,
+		}
+	}
+	return map[string]any{
+		"openapi": "3.0.3",
+		"info": map[string]any{
+			"title":   "Error codes",
+			"version": "1.0.0",
+		},
+		"paths": map[string]any{},
+		"components": map[string]any{
+			"schemas": map[string]any{
+				"Error": map[string]any{
+					"type":     "object",
+					"required": []string{"code", "message", "timestamp"},
+					"properties": map[string]any{
+						"code":       map[string]any{"type": "string", "enum": enum},
+						"message":    map[string]any{"type": "string"},
+						"detail":     map[string]any{"type": "string"},
+						"app_name":   map[string]any{"type": "string"},
+						"info":       map[string]any{"type": "object", "additionalProperties": true},
+						"violations": map[string]any{"type": "array", "items": map[string]any{"$ref": "#/components/schemas/FieldViolation"}},
+						"timestamp":  map[string]any{"type": "integer", "format": "int64", "description": "Unix time in milliseconds"},
+					},
+				},
+				"FieldViolation": map[string]any{
+					"type":     "object",
+					"required": []string{"field", "rule", "message"},
+					"properties": map[string]any{
+						"field":   map[string]any{"type": "string"},
+						"rule":    map[string]any{"type": "string"},
+						"message": map[string]any{"type": "string"},
+						"value":   map[string]any{},
+					},
+				},
+			},
+			"examples":  examples,
+			"responses": responses,
+		},
+	}
+}
+
+func RenderOpenAPIYAML(w io.Writer, codes []Code, appName string) error {
+	enc := yaml.NewEncoder(w)
+	enc.SetIndent(2)
+	if err := enc.Encode(OpenAPI(codes, appName)); err != nil {
+		return err
+	}
+	return enc.Close()
+}
+
+func RenderOpenAPIJSON(w io.Writer, codes []Code, appName string) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(OpenAPI(codes, appName))
+}
+
+// anchor is the GitHub heading anchor of code
+func anchor(code string) string {
+	return strings.ToLower(code)
+}
+
+func yesNo(b bool) string {
+	if b {
+		return "yes"
+	}
+	return "no"
+}
+
+func escapeCell(s string) string {
+	return strings.ReplaceAll(strings.ReplaceAll(s, "|", "\\|"), "\n", " ")
+}
@@ -0,0 +1,123 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"gopkg.in/yaml.v3"
+)
+
+var testCodes = []Code{
+	{
+		Code:        "PAYMENT_DECLINED",
+		Const:       "ErrCodePaymentDeclined",
+		Package:     "billing",
+		Description: "Payment declined",
+		Message:     "Payment declined",
+		HTTPStatus:  402,
+		GRPCCode:    "FailedPrecondition",
+		LogLevel:    "warn",
+		Registered:  true,
+	},
+	{
+		Code:        "NOT_FOUND",
+		Description: "Not found",
+		Message:     "Not found",
+		HTTPStatus:  404,
+		GRPCCode:    "NotFound",
+		LogLevel:    "info",
+		Registered:  true,
+		Definitions: []Definition{
+			{Name: "ErrInvoiceNotFound", Package: "billing", Message: "invoice {invoice_id} not found", InfoKeys: []string{"invoice_id"}},
+		},
+	},
+}
+
+func TestRenderMarkdown(t *testing.T) {
+	var buf bytes.Buffer
+	if err := RenderMarkdown(&buf, testCodes, "billing-service"); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	out := buf.String()
+
+	expected := []string{
+		"| [`PAYMENT_DECLINED`](#payment_declined) | 402 | FailedPrecondition | no | Payment declined |",
+		"## NOT_FOUND",
+		"- Constant: `billing.ErrCodePaymentDeclined`",
+		"- HTTP status: 402 Payment Required",
+		"| `billing.ErrInvoiceNotFound` | invoice {invoice_id} not found | invoice_id |",
+		`"app_name": "billing-service"`,
+		`"invoice_id": "string"`,
+	}
+	for _, s := range expected {
+		if !strings.Contains(out, s) {
+			t.Errorf("Expected markdown to contain %q\n%s", s, out)
+		}
+	}
+}
+
+func TestRenderOpenAPI(t *testing.T) {
+	var buf bytes.Buffer
+	if err := RenderOpenAPIYAML(&buf, testCodes, "billing-service"); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	var doc struct {
+		OpenAPI    string `yaml:"openapi"`
+		Components struct {
+			Schemas map[string]struct {
+				Properties map[string]struct {
+					Enum []string `yaml:"enum"`
+				} `yaml:"properties"`
+			} `yaml:"schemas"`
+			Examples map[string]struct {
+				Value example `yaml:"value"`
+			} `yaml:"examples"`
+			Responses map[string]struct {
+				Description string `yaml:"description"`
+				HTTPStatus  int    `yaml:"x-http-status"`
+			} `yaml:"responses"`
+		} `yaml:"components"`
+	}
+	if err := yaml.Unmarshal(buf.Bytes(), &doc); err != nil {
+		t.Fatalf("Expected valid YAML, got %v", err)
+	}
+
+	if doc.OpenAPI != "3.0.3" {
+		t.Errorf("Expected openapi 3.0.3, got %s", doc.OpenAPI)
+	}
+
+	if enum := doc.Components.Schemas["Error"].Properties["code"].Enum; len(enum) != 2 || enum[0] != "PAYMENT_DECLINED" {
+		t.Errorf("Expected code enum, got %v", enum)
+	}
+
+	if ex := doc.Components.Examples["NOT_FOUND"].Value; ex.Message != "invoice {invoice_id} not found" {
+		t.Errorf("Expected example message from the definition, got %q", ex.Message)
+	}
+
+	if r := doc.Components.Responses["PAYMENT_DECLINED"]; r.HTTPStatus != 402 || r.Description != "402 Payment Required: Payment declined" {
+		t.Errorf("Unexpected response %+v", r)
+	}
+
+	// JSON
+	buf.Reset()
+	if err := RenderOpenAPIJSON(&buf, testCodes, "billing-service"); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if !json.Valid(buf.Bytes()) {
+		t.Error("Expected valid JSON")
+	}
+}
+
+func TestRenderer(t *testing.T) {
+	for _, format := range []string{"markdown", "md", "openapi", "openapi-yaml", "openapi-json"} {
+		if _, err := renderer(format); err != nil {
+			t.Errorf("Expected format %s to be supported, got %v", format, err)
+		}
+	}
+	if _, err := renderer("html"); err == nil {
+		t.Error("Expected error for unsupported format")
+	}
+}
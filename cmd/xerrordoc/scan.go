@@ -0,0 +1,517 @@
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"go/ast"
+	"go/build"
+	"go/parser"
+	"go/token"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+
+	"google.golang.org/grpc/codes"
+)
+
+const codeConstPrefix = "ErrCode"
+
+// Code is the documentation of an error code
+type Code struct {
+	Code        string       `json:"code" yaml:"code"`
+	Const       string       `json:"const,omitempty" yaml:"const,omitempty"`
+	Package     string       `json:"package,omitempty" yaml:"package,omitempty"`
+	Description string       `json:"description,omitempty" yaml:"description,omitempty"`
+	Message     string       `json:"message,omitempty" yaml:"message,omitempty"`
+	HTTPStatus  int          `json:"http_status" yaml:"http_status"`
+	GRPCCode    string       `json:"grpc_code" yaml:"grpc_code"`
+	Retryable   bool         `json:"retryable" yaml:"retryable"`
+	LogLevel    string       `json:"log_level" yaml:"log_level"`
+	Registered  bool         `json:"registered" yaml:"registered"`
+	Definitions []Definition `json:"definitions,omitempty" yaml:"definitions,omitempty"`
+}
+
+// Definition is a sentinel declared with xerror.Define
+type Definition struct {
+	Name     string   `json:"name" yaml:"name"`
+	Package  string   `json:"package" yaml:"package"`
+	Message  string   `json:"message,omitempty" yaml:"message,omitempty"`
+	InfoKeys []string `json:"info_keys,omitempty" yaml:"info_keys,omitempty"`
+}
+
+type codeConst struct {
+	name    string
+	pkg     string
+	decl    *constDecl
+	comment string
+}
+
+type scanner struct {
+	fset *token.FileSet
+	// consts maps an import path to the constants declared by the package
+	consts map[string]map[string]*constDecl
+	// pkgNames maps the import path of a scanned package to its name
+	pkgNames map[string]string
+	codes    []codeConst
+	infos    []infoLit
+	defs     []defCall
+	grpc     map[string]codes.Code
+}
+
+type constant struct {
+	str   string
+	num   int
+	isNum bool
+}
+
+// constDecl is a constant expression resolved in the scope of its file when the catalog is built
+type constDecl struct {
+	expr      ast.Expr
+	scope     *fileScope
+	value     constant
+	ok        bool
+	resolved  bool
+	resolving bool
+}
+
+// fileScope resolves identifiers in the package of the file and selectors through the imports of the file
+type fileScope struct {
+	pkg     string
+	imports []*ast.ImportSpec
+}
+
+type infoLit struct {
+	lit   *ast.CompositeLit
+	scope *fileScope
+}
+
+type defCall struct {
+	name  string
+	pkg   string
+	call  *ast.CallExpr
+	scope *fileScope
+}
+
+// Scan parses the Go files of the patterns which match the build constraints of the current platform, "dir/..."
+// walks dir recursively. Codes come from ErrCode* string constants, xerror.CodeInfo literals and xerror.Define calls.
+// Constants are resolved by package through the imports of each file, so the package declaring a code must be
+// scanned along with the packages using it
+func Scan(patterns ...string) ([]Code, error) {
+	s := &scanner{
+		fset:     token.NewFileSet(),
+		consts:   make(map[string]map[string]*constDecl),
+		pkgNames: make(map[string]string),
+		grpc:     make(map[string]codes.Code),
+	}
+	for c := codes.OK; c <= codes.Unauthenticated; c++ {
+		s.grpc[c.String()] = c
+	}
+	if err := s.loadHTTPStatus(); err != nil {
+		return nil, err
+	}
+	for _, pattern := range patterns {
+		dirs, err := expand(pattern)
+		if err != nil {
+			return nil, err
+		}
+		for _, dir := range dirs {
+			if err = s.parseDir(dir); err != nil {
+				return nil, err
+			}
+		}
+	}
+	return s.build(), nil
+}
+
+func expand(pattern string) ([]string, error) {
+	root, recursive := strings.CutSuffix(pattern, "/...")
+	if !recursive {
+		return []string{pattern}, nil
+	}
+	var dirs []string
+	err := filepath.WalkDir(root, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if !d.IsDir() {
+			return nil
+		}
+		name := d.Name()
+		if path != root && (strings.HasPrefix(name, ".") || strings.HasPrefix(name, "_") || name == "testdata" || name == "vendor") {
+			return filepath.SkipDir
+		}
+		dirs = append(dirs, path)
+		return nil
+	})
+	return dirs, err
+}
+
+// parseDir parses the files of the package in dir which match the build constraints of the current platform,
+// test files and files excluded by build tags such as //go:build ignore are skipped
+func (s *scanner) parseDir(dir string) error {
+	pkg, err := build.ImportDir(dir, 0)
+	if err != nil {
+		if _, ok := err.(*build.NoGoError); ok {
+			return nil
+		}
+		return err
+	}
+	importPath, err := dirImportPath(dir)
+	if err != nil {
+		return err
+	}
+	s.pkgNames[importPath] = pkg.Name
+	files := append(append([]string(nil), pkg.GoFiles...), pkg.CgoFiles...)
+	sort.Strings(files)
+	for _, name := range files {
+		f, err := parser.ParseFile(s.fset, filepath.Join(dir, name), nil, parser.ParseComments)
+		if err != nil {
+			return err
+		}
+		s.parseFile(importPath, pkg.Name, f)
+	}
+	return nil
+}
+
+func (s *scanner) parseFile(importPath, pkg string, f *ast.File) {
+	scope := &fileScope{pkg: importPath, imports: f.Imports}
+	for _, decl := range f.Decls {
+		gen, ok := decl.(*ast.GenDecl)
+		if !ok || gen.Tok != token.CONST {
+			continue
+		}
+		for _, spec := range gen.Specs {
+			vs := spec.(*ast.ValueSpec)
+			for i, name := range vs.Names {
+				if i >= len(vs.Values) {
+					continue
+				}
+				d := s.declare(importPath, name.Name, vs.Values[i], scope)
+				if strings.HasPrefix(name.Name, codeConstPrefix) {
+					s.codes = append(s.codes, codeConst{
+						name:    name.Name,
+						pkg:     pkg,
+						decl:    d,
+						comment: strings.TrimSpace(commentText(vs.Doc, vs.Comment)),
+					})
+				}
+			}
+		}
+	}
+	ast.Inspect(f, func(n ast.Node) bool {
+		switch n := n.(type) {
+		case *ast.CompositeLit:
+			s.inspectCompositeLit(n, scope)
+		case *ast.ValueSpec:
+			for i, name := range n.Names {
+				if i < len(n.Values) {
+					if call, ok := n.Values[i].(*ast.CallExpr); ok && identName(call.Fun) == "Define" {
+						s.defs = append(s.defs, defCall{name: name.Name, pkg: pkg, call: call, scope: scope})
+					}
+				}
+			}
+		}
+		return true
+	})
+}
+
+func (s *scanner) declare(importPath, name string, expr ast.Expr, scope *fileScope) *constDecl {
+	pkgConsts, ok := s.consts[importPath]
+	if !ok {
+		pkgConsts = make(map[string]*constDecl)
+		s.consts[importPath] = pkgConsts
+	}
+	d := &constDecl{expr: expr, scope: scope}
+	pkgConsts[name] = d
+	return d
+}
+
+func (s *scanner) inspectCompositeLit(lit *ast.CompositeLit, scope *fileScope) {
+	switch t := lit.Type.(type) {
+	case *ast.ArrayType:
+		if identName(t.Elt) != "CodeInfo" {
+			return
+		}
+		for _, elt := range lit.Elts {
+			if el, ok := elt.(*ast.CompositeLit); ok && el.Type == nil {
+				s.infos = append(s.infos, infoLit{lit: el, scope: scope})
+			}
+		}
+	default:
+		if identName(lit.Type) == "CodeInfo" {
+			s.infos = append(s.infos, infoLit{lit: lit, scope: scope})
+		}
+	}
+}
+
+func (s *scanner) build() []Code {
+	byCode := make(map[string]*Code)
+	order := make([]string, 0)
+	get := func(code string) *Code {
+		c, ok := byCode[code]
+		if !ok {
+			c = &Code{
+				Code:       code,
+				HTTPStatus: 500,
+				GRPCCode:   codes.Internal.String(),
+				LogLevel:   "error",
+			}
+			byCode[code] = c
+			order = append(order, code)
+		}
+		return c
+	}
+
+	for _, info := range s.infos {
+		scope := info.scope
+		fields := make(map[string]ast.Expr, len(info.lit.Elts))
+		for _, elt := range info.lit.Elts {
+			if kv, ok := elt.(*ast.KeyValueExpr); ok {
+				if key, ok := kv.Key.(*ast.Ident); ok {
+					fields[key.Name] = kv.Value
+				}
+			}
+		}
+		codeValue, ok := s.eval(scope, fields["Code"])
+		if !ok || codeValue.isNum {
+			continue
+		}
+		c := get(codeValue.str)
+		c.Registered = true
+		c.HTTPStatus = 0
+		c.GRPCCode = codes.OK.String()
+		c.LogLevel = "info"
+		if v, ok := s.eval(scope, fields["HTTPStatus"]); ok && v.isNum {
+			c.HTTPStatus = v.num
+		}
+		if name := identName(fields["GRPCCode"]); name != "" {
+			if _, known := s.grpc[name]; known {
+				c.GRPCCode = name
+			}
+		}
+		if v, ok := s.eval(scope, fields["Message"]); ok && !v.isNum {
+			c.Message = v.str
+		}
+		if id, ok := fields["Retryable"].(*ast.Ident); ok {
+			c.Retryable = id.Name == "true"
+		}
+		if name := identName(fields["LogLevel"]); name != "" {
+			c.LogLevel = strings.ToLower(strings.TrimSuffix(name, "Level"))
+		}
+	}
+
+	for _, cc := range s.codes {
+		v, ok := s.resolve(cc.decl)
+		if !ok || v.isNum {
+			continue
+		}
+		c := get(v.str)
+		if c.Const == "" {
+			c.Const = cc.name
+			c.Package = cc.pkg
+			c.Description = cc.comment
+		}
+	}
+
+	for _, d := range s.defs {
+		if len(d.call.Args) < 2 {
+			continue
+		}
+		codeValue, ok := s.eval(d.scope, d.call.Args[0])
+		if !ok || codeValue.isNum {
+			continue
+		}
+		def := Definition{Name: d.name, Package: d.pkg}
+		if v, ok := s.eval(d.scope, d.call.Args[1]); ok && !v.isNum {
+			def.Message = v.str
+		}
+		for _, arg := range d.call.Args[2:] {
+			if v, ok := s.eval(d.scope, arg); ok && !v.isNum {
+				def.InfoKeys = append(def.InfoKeys, v.str)
+			}
+		}
+		c := get(codeValue.str)
+		c.Definitions = append(c.Definitions, def)
+	}
+
+	out := make([]Code, 0, len(order))
+	for _, code := range order {
+		c := byCode[code]
+		if c.Description == "" {
+			c.Description = c.Message
+		}
+		out = append(out, *c)
+	}
+	return out
+}
+
+// eval resolves string and int literals and the constants of the scanned packages and net/http
+func (s *scanner) eval(scope *fileScope, e ast.Expr) (constant, bool) {
+	switch e := e.(type) {
+	case *ast.BasicLit:
+		switch e.Kind {
+		case token.STRING:
+			v, err := strconv.Unquote(e.Value)
+			return constant{str: v}, err == nil
+		case token.INT:
+			v, err := strconv.Atoi(e.Value)
+			return constant{num: v, isNum: true}, err == nil
+		}
+	case *ast.Ident:
+		return s.lookup(scope.pkg, e.Name)
+	case *ast.SelectorExpr:
+		if x, ok := e.X.(*ast.Ident); ok {
+			if importPath, ok := s.importPathOf(scope, x.Name); ok {
+				return s.lookup(importPath, e.Sel.Name)
+			}
+		}
+	case *ast.ParenExpr:
+		return s.eval(scope, e.X)
+	}
+	return constant{}, false
+}
+
+func (s *scanner) lookup(importPath, name string) (constant, bool) {
+	d, ok := s.consts[importPath][name]
+	if !ok {
+		return constant{}, false
+	}
+	return s.resolve(d)
+}
+
+func (s *scanner) resolve(d *constDecl) (constant, bool) {
+	if d.resolved {
+		return d.value, d.ok
+	}
+	if d.resolving {
+		return constant{}, false
+	}
+	d.resolving = true
+	d.value, d.ok = s.eval(d.scope, d.expr)
+	d.resolving, d.resolved = false, true
+	return d.value, d.ok
+}
+
+// importPathOf returns the import path of the package name refers to in the file of scope
+func (s *scanner) importPathOf(scope *fileScope, name string) (string, bool) {
+	for _, imp := range scope.imports {
+		importPath, err := strconv.Unquote(imp.Path.Value)
+		if err != nil {
+			continue
+		}
+		if imp.Name != nil {
+			if imp.Name.Name == name {
+				return importPath, true
+			}
+			continue
+		}
+		if s.packageName(importPath) == name {
+			return importPath, true
+		}
+	}
+	return "", false
+}
+
+// packageName is the name of a scanned package, or the last element of the import path without its major version
+func (s *scanner) packageName(importPath string) string {
+	if name, ok := s.pkgNames[importPath]; ok {
+		return name
+	}
+	name := path.Base(importPath)
+	if len(name) > 1 && name[0] == 'v' && strings.Trim(name[1:], "0123456789") == "" {
+		name = path.Base(path.Dir(importPath))
+	}
+	return name
+}
+
+// loadHTTPStatus reads the http.Status* constants from the standard library source
+func (s *scanner) loadHTTPStatus() error {
+	pkg, err := build.Import("net/http", "", build.FindOnly)
+	if err != nil {
+		return fmt.Errorf("cannot find net/http to resolve http.Status* constants: %w", err)
+	}
+	f, err := parser.ParseFile(s.fset, filepath.Join(pkg.Dir, "status.go"), nil, 0)
+	if err != nil {
+		return fmt.Errorf("cannot parse net/http to resolve http.Status* constants: %w", err)
+	}
+	scope := &fileScope{pkg: "net/http"}
+	for _, decl := range f.Decls {
+		gen, ok := decl.(*ast.GenDecl)
+		if !ok || gen.Tok != token.CONST {
+			continue
+		}
+		for _, spec := range gen.Specs {
+			vs := spec.(*ast.ValueSpec)
+			for i, name := range vs.Names {
+				if i < len(vs.Values) {
+					s.declare("net/http", name.Name, vs.Values[i], scope)
+				}
+			}
+		}
+	}
+	if v, ok := s.lookup("net/http", "StatusOK"); !ok || v.num != 200 {
+		return fmt.Errorf("cannot resolve http.Status* constants from %s", pkg.Dir)
+	}
+	return nil
+}
+
+// dirImportPath returns the import path of dir from the go.mod of its module, or its absolute path outside of a module
+func dirImportPath(dir string) (string, error) {
+	abs, err := filepath.Abs(dir)
+	if err != nil {
+		return "", err
+	}
+	for root := abs; ; {
+		if module, ok := modulePath(filepath.Join(root, "go.mod")); ok {
+			rel, err := filepath.Rel(root, abs)
+			if err != nil {
+				return "", err
+			}
+			return path.Join(module, filepath.ToSlash(rel)), nil
+		}
+		parent := filepath.Dir(root)
+		if parent == root {
+			return filepath.ToSlash(abs), nil
+		}
+		root = parent
+	}
+}
+
+func modulePath(gomod string) (string, bool) {
+	f, err := os.Open(gomod)
+	if err != nil {
+		return "", false
+	}
+	defer f.Close()
+	sc := bufio.NewScanner(f)
+	for sc.Scan() {
+		if module, ok := strings.CutPrefix(strings.TrimSpace(sc.Text()), "module "); ok {
+			return strings.Trim(strings.TrimSpace(module), `"`), true
+		}
+	}
+	return "", false
+}
+
+// identName returns the name of an identifier or the selected name of a selector expression
+func identName(e ast.Expr) string {
+	switch e := e.(type) {
+	case *ast.Ident:
+		return e.Name
+	case *ast.SelectorExpr:
+		return e.Sel.Name
+	}
+	return ""
+}
+
+func commentText(groups ...*ast.CommentGroup) string {
+	for _, g := range groups {
+		if g != nil {
+			return g.Text()
+		}
+	}
+	return ""
+}
@@ -0,0 +1,115 @@
+package main
+
+import (
+	"testing"
+)
+
+func findCode(codes []Code, code string) (Code, bool) {
+	for _, c := range codes {
+		if c.Code == code {
+			return c, true
+		}
+	}
+	return Code{}, false
+}
+
+func TestScan(t *testing.T) {
+	codes, err := Scan("./testdata/...", "../../xerror")
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	testCases := []struct {
+		code        string
+		httpStatus  int
+		grpcCode    string
+		retryable   bool
+		logLevel    string
+		registered  bool
+		description string
+	}{
+		{"PAYMENT_DECLINED", 402, "FailedPrecondition", false, "warn", true, "ErrCodePaymentDeclined is returned when the card issuer declines the payment"},
+		{"QUOTA_EXCEEDED", 429, "ResourceExhausted", true, "info", true, "Quota exceeded"},
+		{"UNREGISTERED", 500, "Internal", false, "error", false, "ErrCodeUnregistered has no registration"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.code, func(t *testing.T) {
+			c, ok := findCode(codes, tc.code)
+			if !ok {
+				t.Fatalf("Expected code %s to be found in %v", tc.code, codes)
+			}
+			if c.HTTPStatus != tc.httpStatus {
+				t.Errorf("Expected http status %d, got %d", tc.httpStatus, c.HTTPStatus)
+			}
+			if c.GRPCCode != tc.grpcCode {
+				t.Errorf("Expected grpc code %s, got %s", tc.grpcCode, c.GRPCCode)
+			}
+			if c.Retryable != tc.retryable {
+				t.Errorf("Expected retryable %v, got %v", tc.retryable, c.Retryable)
+			}
+			if c.LogLevel != tc.logLevel {
+				t.Errorf("Expected log level %s, got %s", tc.logLevel, c.LogLevel)
+			}
+			if c.Registered != tc.registered {
+				t.Errorf("Expected registered %v, got %v", tc.registered, c.Registered)
+			}
+			if c.Description != tc.description {
+				t.Errorf("Expected description %q, got %q", tc.description, c.Description)
+			}
+		})
+	}
+
+	// Files excluded by their build constraints are not scanned
+	for _, code := range []string{"GENERATED_PAYMENT_DECLINED", "LEGACY"} {
+		if _, ok := findCode(codes, code); ok {
+			t.Errorf("Expected %s of a file excluded by build tags to be skipped", code)
+		}
+	}
+
+	// Definitions of codes declared in another package
+	c, ok := findCode(codes, "NOT_FOUND")
+	if !ok || len(c.Definitions) != 1 {
+		t.Fatalf("Expected the definition of NOT_FOUND, got %v", c)
+	}
+	d := c.Definitions[0]
+	if d.Name != "ErrInvoiceNotFound" || d.Package != "billing" || d.Message != "invoice {invoice_id} not found" ||
+		len(d.InfoKeys) != 1 || d.InfoKeys[0] != "invoice_id" {
+		t.Errorf("Unexpected definition %+v", d)
+	}
+
+	// Constants with the same name in another package don't override the ones of xerror and net/http
+	if c.HTTPStatus != 404 {
+		t.Errorf("Expected http status 404 for NOT_FOUND, got %d", c.HTTPStatus)
+	}
+	c, ok = findCode(codes, "SHADOW_NOT_FOUND")
+	if !ok || len(c.Definitions) != 1 || c.Definitions[0].Name != "ErrShadowNotFound" || c.Definitions[0].Package != "shadow" {
+		t.Errorf("Expected the definition of SHADOW_NOT_FOUND, got %+v", c)
+	}
+}
+
+func TestScan_Builtin(t *testing.T) {
+	codes, err := Scan("../../xerror")
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	c, ok := findCode(codes, "RATE_LIMIT_EXCEEDED")
+	if !ok {
+		t.Fatal("Expected built-in codes to be found")
+	}
+	if c.HTTPStatus != 429 || c.GRPCCode != "ResourceExhausted" || !c.Retryable || c.Const != "ErrCodeRateLimitExceeded" {
+		t.Errorf("Unexpected metadata %+v", c)
+	}
+
+	// Non standard status declared in the package
+	if c, _ := findCode(codes, "CLIENT_REQUEST_CANCELED"); c.HTTPStatus != 499 {
+		t.Errorf("Expected http status 499, got %d", c.HTTPStatus)
+	}
+}
+
+func TestScan_InvalidDir(t *testing.T) {
+	if _, err := Scan("./does-not-exist"); err == nil {
+		t.Error("Expected error for missing directory")
+	}
+}
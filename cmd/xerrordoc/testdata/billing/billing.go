@@ -0,0 +1,36 @@
+package billing
+
+import (
+	"net/http"
+
+	"github.com/kurzgesagtz/xgo/xerror"
+	"go.uber.org/zap/zapcore"
+	"google.golang.org/grpc/codes"
+)
+
+const (
+	// ErrCodePaymentDeclined is returned when the card issuer declines the payment
+	ErrCodePaymentDeclined = "PAYMENT_DECLINED"
+	ErrCodeQuotaExceeded   = "QUOTA_EXCEEDED"
+	// ErrCodeUnregistered has no registration
+	ErrCodeUnregistered = "UNREGISTERED"
+)
+
+var ErrInvoiceNotFound = xerror.Define(xerror.ErrCodeNotFound, "invoice {invoice_id} not found", "invoice_id")
+
+func init() {
+	xerror.MustRegisterCode(xerror.CodeInfo{
+		Code:       ErrCodePaymentDeclined,
+		HTTPStatus: http.StatusPaymentRequired,
+		GRPCCode:   codes.FailedPrecondition,
+		Message:    "Payment declined",
+		LogLevel:   zapcore.WarnLevel,
+	})
+	_ = xerror.RegisterCode(xerror.CodeInfo{
+		Code:       "QUOTA_EXCEEDED",
+		HTTPStatus: 429,
+		GRPCCode:   codes.ResourceExhausted,
+		Message:    "Quota exceeded",
+		Retryable:  true,
+	})
+}
@@ -0,0 +1,6 @@
+//go:build legacy
+
+package billing
+
+// ErrCodeLegacy is only declared by builds with the legacy tag
+const ErrCodeLegacy = "LEGACY"
@@ -0,0 +1,6 @@
+//go:build ignore
+
+// gen.go is a generator of the package, it is excluded from the package by its build tag
+package main
+
+const ErrCodePaymentDeclined = "GENERATED_PAYMENT_DECLINED"
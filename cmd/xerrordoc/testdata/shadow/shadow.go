@@ -0,0 +1,11 @@
+package shadow
+
+import "github.com/kurzgesagtz/xgo/xerror"
+
+// ErrCodeNotFound has the name of xerror.ErrCodeNotFound in another package
+const ErrCodeNotFound = "SHADOW_NOT_FOUND"
+
+// StatusPaymentRequired has the name of http.StatusPaymentRequired in another package
+const StatusPaymentRequired = 418
+
+var ErrShadowNotFound = xerror.Define(ErrCodeNotFound, "shadow not found")
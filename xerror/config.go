@@ -0,0 +1,99 @@
+package xerror
+
+import (
+	"go.uber.org/zap/zapcore"
+	"os"
+	"sync/atomic"
+	"time"
+)
+
+const xErrorAppNameKey = "APP_NAME" // default is localhost
+
+// DetailMode controls the Detail of new xerror when WithDetail is not used
+type DetailMode int32
+
+const (
+	// DetailCaller sets Detail to the caller, it is the default
+	DetailCaller DetailMode = iota
+	// DetailNone leaves Detail empty
+	DetailNone
+)
+
+var (
+	appName      atomic.Pointer[string]
+	stackCapture atomic.Bool
+	stackLevel   atomic.Int32
+	detailMode   atomic.Int32
+	callerSkip   atomic.Int32
+	clock        atomic.Pointer[func() time.Time]
+)
+
+func init() {
+	name := "local"
+	if aName, ok := os.LookupEnv(xErrorAppNameKey); ok {
+		name = aName
+	}
+	SetAppName(name)
+	stackCapture.Store(true)
+	stackLevel.Store(int32(zapcore.DebugLevel))
+}
+
+// SetAppName sets the app name of new xerror, it defaults to the APP_NAME environment variable
+func SetAppName(name string) {
+	appName.Store(&name)
+}
+
+func AppName() string {
+	return *appName.Load()
+}
+
+// SetStackCapture turns the capture of Callers on or off, disable it on hot paths where the stack is never used
+func SetStackCapture(enabled bool) {
+	stackCapture.Store(enabled)
+}
+
+// SetStackLevel captures Callers only for codes whose registered log level is at least level, unregistered codes
+// are treated as zapcore.ErrorLevel. Default is zapcore.DebugLevel which captures every stack
+func SetStackLevel(level zapcore.Level) {
+	stackLevel.Store(int32(level))
+}
+
+func SetDetailMode(mode DetailMode) {
+	detailMode.Store(int32(mode))
+}
+
+// SetCallerSkip skips additional frames for Caller and Callers of new xerror, use it when errors are always created
+// through a helper of the application
+func SetCallerSkip(skip int) {
+	if skip < 0 {
+		skip = 0
+	}
+	callerSkip.Store(int32(skip))
+}
+
+// SetClock sets the clock used by TimestampNow, nil restores time.Now
+func SetClock(now func() time.Time) {
+	if now == nil {
+		clock.Store(nil)
+		return
+	}
+	clock.Store(&now)
+}
+
+func now() time.Time {
+	if fn := clock.Load(); fn != nil {
+		return (*fn)()
+	}
+	return time.Now()
+}
+
+func captureStack(info CodeInfo, registered bool) bool {
+	if !stackCapture.Load() {
+		return false
+	}
+	level := zapcore.ErrorLevel
+	if registered {
+		level = info.LogLevel
+	}
+	return level >= zapcore.Level(stackLevel.Load())
+}
@@ -0,0 +1,143 @@
+package xerror
+
+import (
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"go.uber.org/zap/zapcore"
+)
+
+func TestSetAppName(t *testing.T) {
+	defer SetAppName(AppName())
+
+	SetAppName("billing")
+	if AppName() != "billing" {
+		t.Errorf("Expected app name billing, got %s", AppName())
+	}
+
+	if err := NewError(ErrCodeNotFound); err.AppName != "billing" {
+		t.Errorf("Expected new error app name billing, got %s", err.AppName)
+	}
+
+	// Concurrent access
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(2)
+		go func() {
+			defer wg.Done()
+			SetAppName("billing")
+		}()
+		go func() {
+			defer wg.Done()
+			_ = NewError(ErrCodeNotFound)
+		}()
+	}
+	wg.Wait()
+}
+
+func TestSetStackCapture(t *testing.T) {
+	defer SetStackCapture(true)
+
+	SetStackCapture(false)
+	err := NewError(ErrCodeInternalError)
+	if err.Callers != nil {
+		t.Error("Expected no stack when capture is disabled")
+	}
+	if err.Caller == "" {
+		t.Error("Expected caller to be kept when capture is disabled")
+	}
+
+	SetStackCapture(true)
+	if err := NewError(ErrCodeInternalError); err.Callers == nil {
+		t.Error("Expected stack when capture is enabled")
+	}
+}
+
+func TestSetStackLevel(t *testing.T) {
+	defer SetStackLevel(zapcore.DebugLevel)
+
+	SetStackLevel(zapcore.ErrorLevel)
+
+	testCases := []struct {
+		code        string
+		expectStack bool
+	}{
+		{ErrCodeNotFound, false},
+		{ErrCodeInvalidRequest, false},
+		{ErrCodeInternalError, true},
+		{"UNREGISTERED_CODE", true},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.code, func(t *testing.T) {
+			err := NewError(tc.code)
+			if (err.Callers != nil) != tc.expectStack {
+				t.Errorf("Expected stack %v, got %v", tc.expectStack, err.Callers != nil)
+			}
+		})
+	}
+}
+
+func TestSetDetailMode(t *testing.T) {
+	defer SetDetailMode(DetailCaller)
+
+	if err := NewError(ErrCodeNotFound); err.Detail != err.Caller {
+		t.Errorf("Expected detail to be the caller by default, got %s", err.Detail)
+	}
+
+	SetDetailMode(DetailNone)
+	if err := NewError(ErrCodeNotFound); err.Detail != "" {
+		t.Errorf("Expected empty detail, got %s", err.Detail)
+	}
+
+	if err := NewError(ErrCodeNotFound, WithDetail("check the id")); err.Detail != "check the id" {
+		t.Errorf("Expected explicit detail, got %s", err.Detail)
+	}
+}
+
+func newErrorHelper() *Error {
+	return NewError(ErrCodeNotFound)
+}
+
+func TestSetCallerSkip(t *testing.T) {
+	defer SetCallerSkip(0)
+
+	// The first frame is NewError itself
+	if err := newErrorHelper(); !strings.HasSuffix(err.Frames()[1].Function, "newErrorHelper") {
+		t.Errorf("Expected the helper in the stack, got %s", err.Frames()[1].Function)
+	}
+
+	SetCallerSkip(1)
+	err := newErrorHelper()
+	// The helper takes the place of NewError
+	if frames := err.Frames(); !strings.HasSuffix(frames[1].Function, "TestSetCallerSkip") {
+		t.Errorf("Expected the helper to be skipped, got %s", frames[1].Function)
+	}
+	if !strings.Contains(err.Caller, "config_test.go") {
+		t.Errorf("Expected caller in the test, got %s", err.Caller)
+	}
+}
+
+func TestSetClock(t *testing.T) {
+	defer SetClock(nil)
+
+	fixed := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+	SetClock(func() time.Time {
+		return fixed
+	})
+
+	if ts := time.Time(TimestampNow()); !ts.Equal(fixed) {
+		t.Errorf("Expected %v, got %v", fixed, ts)
+	}
+
+	if ts := time.Time(NewError(ErrCodeNotFound).Timestamp); !ts.Equal(fixed) {
+		t.Errorf("Expected new error timestamp %v, got %v", fixed, ts)
+	}
+
+	SetClock(nil)
+	if ts := time.Time(TimestampNow()); ts.Equal(fixed) {
+		t.Error("Expected the real clock to be restored")
+	}
+}
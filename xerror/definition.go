@@ -0,0 +1,65 @@
+package xerror
+
+import "fmt"
+
+// Definition is a package level sentinel of a domain error. Errors created by New get their own caller and stack
+// and match the definition with errors.Is, the code alone is not enough to match
+//
+//	var ErrUserNotFound = xerror.Define(xerror.ErrCodeNotFound, "user {user_id} not found", "user_id")
+//
+//	err := ErrUserNotFound.New(id)
+//	errors.Is(err, ErrUserNotFound) // true
+type Definition struct {
+	Code string
+	// Message is the default message, {name} placeholders are replaced with the Info of the error
+	Message string
+	// InfoKeys are the Info keys of the values given to New and Wrap, in order
+	InfoKeys []string
+}
+
+func Define(code, message string, infoKeys ...string) *Definition {
+	return &Definition{
+		Code:     code,
+		Message:  message,
+		InfoKeys: infoKeys,
+	}
+}
+
+// Error implements error so a definition can be the target of errors.Is
+func (d *Definition) Error() string {
+	return fmt.Sprintf("%s: %s", d.Code, d.Message)
+}
+
+// New creates an xerror of the definition, values are stored in Info under InfoKeys in the same order
+func (d *Definition) New(values ...any) *Error {
+	return newError(d.Code, d.options(nil, values)...)
+}
+
+// Wrap is New with a cause
+func (d *Definition) Wrap(cause error, values ...any) *Error {
+	return newError(d.Code, d.options(cause, values)...)
+}
+
+func (d *Definition) options(cause error, values []any) []ErrorOptionFunc {
+	fn := make([]ErrorOptionFunc, 0, len(values)+3)
+	for i, v := range values {
+		if i < len(d.InfoKeys) {
+			fn = append(fn, WithJSONInfo(d.InfoKeys[i], v))
+		}
+	}
+	if cause != nil {
+		fn = append(fn, WithCause(cause))
+	}
+	return append(fn, func(err *Error) *Error {
+		err.def = d
+		if d.Message != "" {
+			err.Message = renderTemplate(d.Message, err.Info)
+		}
+		return err
+	})
+}
+
+// Definition returns the definition err was created from, nil for errors created with NewError
+func (err *Error) Definition() *Definition {
+	return err.def
+}
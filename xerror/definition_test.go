@@ -0,0 +1,102 @@
+package xerror
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+var (
+	errTestUserNotFound  = Define(ErrCodeNotFound, "user {user_id} not found", "user_id")
+	errTestOrderNotFound = Define(ErrCodeNotFound, "order not found")
+)
+
+func TestDefinition_New(t *testing.T) {
+	err := errTestUserNotFound.New("u-1")
+
+	if err.Code != ErrCodeNotFound {
+		t.Errorf("Expected code %s, got %s", ErrCodeNotFound, err.Code)
+	}
+
+	if err.Message != "user u-1 not found" {
+		t.Errorf("Expected rendered message, got %q", err.Message)
+	}
+
+	if err.Info["user_id"] != "u-1" {
+		t.Errorf("Expected info user_id u-1, got %v", err.Info["user_id"])
+	}
+
+	if !strings.Contains(err.Caller, "definition_test.go") {
+		t.Errorf("Expected caller in the test file, got %s", err.Caller)
+	}
+
+	if err.Definition() != errTestUserNotFound {
+		t.Error("Expected the definition to be kept")
+	}
+
+	// Each error has its own stack
+	other := errTestUserNotFound.New("u-2")
+	if err.Callers == other.Callers || err.Caller == other.Caller {
+		t.Error("Expected a fresh stack per error")
+	}
+
+	// Extra values are ignored, missing ones keep the placeholder
+	if err := errTestUserNotFound.New(); err.Message != "user {user_id} not found" {
+		t.Errorf("Expected placeholder to be kept, got %q", err.Message)
+	}
+	if err := errTestOrderNotFound.New("ignored"); err.Info != nil {
+		t.Errorf("Expected no info, got %v", err.Info)
+	}
+}
+
+func TestDefinition_Is(t *testing.T) {
+	err := errTestUserNotFound.New("u-1")
+
+	if !errors.Is(err, errTestUserNotFound) {
+		t.Error("Expected errors.Is to match the definition")
+	}
+
+	if errors.Is(err, errTestOrderNotFound) {
+		t.Error("Expected no match with another definition of the same code")
+	}
+
+	// Code matching still works
+	if !IsErrorCode(err, ErrCodeNotFound) || !errors.Is(err, &Error{Code: ErrCodeNotFound}) {
+		t.Error("Expected code matching to work")
+	}
+
+	// Errors created with NewError don't match any definition
+	if errors.Is(NewError(ErrCodeNotFound), errTestUserNotFound) {
+		t.Error("Expected no match without definition")
+	}
+
+	// Through wrapping
+	wrapped := Wrap(err, ErrCodeInternalError)
+	if !errors.Is(wrapped, errTestUserNotFound) {
+		t.Error("Expected errors.Is to match a wrapped definition error")
+	}
+
+	// Redacted copies keep the definition
+	if !errors.Is(err.Public(), errTestUserNotFound) {
+		t.Error("Expected public copy to match the definition")
+	}
+}
+
+func TestDefinition_Wrap(t *testing.T) {
+	cause := errors.New("record not found")
+	err := errTestUserNotFound.Wrap(cause, "u-1")
+
+	if !errors.Is(err, cause) || !errors.Is(err, errTestUserNotFound) {
+		t.Error("Expected both the cause and the definition to match")
+	}
+
+	if err.Message != "user u-1 not found" {
+		t.Errorf("Expected rendered message, got %q", err.Message)
+	}
+}
+
+func TestDefinition_Error(t *testing.T) {
+	if s := errTestOrderNotFound.Error(); s != "NOT_FOUND: order not found" {
+		t.Errorf("Expected %q, got %q", "NOT_FOUND: order not found", s)
+	}
+}
@@ -2,58 +2,63 @@ package xerror
 
 import (
 	"fmt"
-	"github.com/pkg/errors"
-	"os"
 	"runtime"
+	"strings"
 )
 
-const xErrorAppNameKey = "APP_NAME" // default is localhost
-var appName = "local"
+func NewError(code string, fn ...ErrorOptionFunc) *Error {
+	return buildError(3, code, fn)
+}
 
-func init() {
-	if aName, ok := os.LookupEnv(xErrorAppNameKey); ok {
-		appName = aName
-	}
+func newError(code string, fn ...ErrorOptionFunc) *Error {
+	return buildError(4, code, fn)
 }
 
-func NewError(code string, fn ...ErrorOptionFunc) *Error {
-	c := caller(2)
-	cs := callers(2)
+func buildError(skip int, code string, fn []ErrorOptionFunc) *Error {
+	skip += int(callerSkip.Load())
+	info, registered := LookupCode(code)
+	c := caller(skip)
 	err := &Error{
 		Code:      code,
-		AppName:   appName,
+		AppName:   AppName(),
 		Caller:    c,
-		Callers:   cs,
-		Detail:    c,
 		Timestamp: TimestampNow(),
 	}
+	if captureStack(info, registered) {
+		err.Callers = callers(skip)
+	}
+	if DetailMode(detailMode.Load()) == DetailCaller {
+		err.Detail = c
+	}
 	for _, optionFunc := range fn {
 		err = optionFunc(err)
 	}
+	if err.Message == "" && registered {
+		err.Message = info.Message
+	}
 	return err
 }
 
-func newError(code string, fn ...ErrorOptionFunc) *Error {
-	c := caller(3)
-	cs := callers(3)
-	err := &Error{
+// newRemoteError creates an xerror decoded from a peer, the local caller, stack and app name don't describe it
+func newRemoteError(code, msg string) *Error {
+	if info, ok := LookupCode(code); ok && msg == "" {
+		msg = info.Message
+	}
+	return &Error{
 		Code:      code,
-		AppName:   appName,
-		Caller:    c,
-		Callers:   cs,
-		Detail:    c,
+		Message:   msg,
 		Timestamp: TimestampNow(),
 	}
-	for _, optionFunc := range fn {
-		err = optionFunc(err)
-	}
-	return err
+}
+
+// Wrap creates a new xerror with code which keeps err as its cause
+func Wrap(err error, code string, fn ...ErrorOptionFunc) *Error {
+	return newError(code, append([]ErrorOptionFunc{WithCause(err)}, fn...)...)
 }
 
 func callers(skip int) *stack {
-	const depth = 32
-	var pcs [depth]uintptr
-	n := runtime.Callers(skip, pcs[:])
+	pcs := make([]uintptr, stackDepth.Load())
+	n := runtime.Callers(skip, pcs)
 	var st stack = pcs[0:n]
 	return &st
 }
@@ -66,26 +71,57 @@ func caller(skip int) string {
 	return ""
 }
 
-// IsErrorCode Check xerror has same code
+// IsErrorCode Check xerror has same code, aggregated errors match when any of them has the code
 func IsErrorCode(err error, code string) bool {
 	if err == nil {
 		return false
 	}
-	var apiErr *Error
-	if errors.As(err, &apiErr) {
+	apiErr, multiErr := lookupError(err)
+	if apiErr != nil {
 		return apiErr.Code == code
-	} else {
-		// if it isn't api.Error then make it as internal xerror
-		return ErrCodeInternalError == code
 	}
+	if multiErr != nil {
+		for _, e := range multiErr.errs {
+			if IsErrorCode(e, code) {
+				return true
+			}
+		}
+		return false
+	}
+	// if it isn't api.Error then make it as internal xerror
+	return ErrCodeInternalError == code
 }
 
-// GetErrorMessage - Get xerror message
+// GetErrorCode - Get xerror code, non xerror is treated as ErrCodeInternalError and aggregated errors return the dominant code
+func GetErrorCode(err error) string {
+	if err == nil {
+		return ""
+	}
+	apiErr, multiErr := lookupError(err)
+	if apiErr != nil {
+		return apiErr.Code
+	}
+	if multiErr != nil {
+		return multiErr.Code()
+	}
+	return ErrCodeInternalError
+}
+
+// GetErrorMessage - Get xerror message, messages of aggregated errors are joined with "; "
 func GetErrorMessage(err error) string {
-	var apiErr *Error
-	if errors.As(err, &apiErr) {
+	apiErr, multiErr := lookupError(err)
+	if apiErr != nil {
 		return apiErr.Message
 	}
+	if multiErr != nil {
+		msgs := make([]string, 0, len(multiErr.errs))
+		for _, e := range multiErr.errs {
+			if msg := GetErrorMessage(e); msg != "" {
+				msgs = append(msgs, msg)
+			}
+		}
+		return strings.Join(msgs, "; ")
+	}
 
 	return ""
 }
@@ -2,17 +2,21 @@ package xerror
 
 import (
 	"errors"
+	"fmt"
 	"os"
+	"strings"
 	"testing"
+
+	"gorm.io/gorm"
 )
 
 func TestNewError(t *testing.T) {
 	// Save original app name and restore it after the test
-	originalAppName := appName
-	defer func() { appName = originalAppName }()
+	originalAppName := AppName()
+	defer SetAppName(originalAppName)
 
 	// Test with default app name
-	appName = "test-app"
+	SetAppName("test-app")
 	err := NewError("TEST_CODE")
 
 	if err == nil {
@@ -45,12 +49,12 @@ func TestNewError(t *testing.T) {
 
 func TestNewErrorWithEnv(t *testing.T) {
 	// Save original app name and restore it after the test
-	originalAppName := appName
-	defer func() { appName = originalAppName }()
+	originalAppName := AppName()
+	defer SetAppName(originalAppName)
 
-	// Set environment variable and update appName directly
+	// Set environment variable and update the app name directly
 	os.Setenv(xErrorAppNameKey, "env-app")
-	appName = "env-app"
+	SetAppName("env-app")
 
 	err := NewError("TEST_CODE")
 
@@ -161,3 +165,72 @@ func TestCallers(t *testing.T) {
 		t.Error("Expected non-empty callers stack")
 	}
 }
+
+func TestWrap(t *testing.T) {
+	cause := gorm.ErrRecordNotFound
+	err := Wrap(cause, ErrCodeNotFound, WithMessage("user not found"))
+
+	if err.Code != ErrCodeNotFound {
+		t.Errorf("Expected code %s, got %s", ErrCodeNotFound, err.Code)
+	}
+
+	if err.Cause != cause {
+		t.Errorf("Expected cause %v, got %v", cause, err.Cause)
+	}
+
+	if !strings.Contains(err.Caller, "error_test.go") {
+		t.Errorf("Expected caller in error_test.go, got %s", err.Caller)
+	}
+
+	// Cause is reachable through the standard library helpers
+	if !errors.Is(err, gorm.ErrRecordNotFound) {
+		t.Error("Expected errors.Is to match the wrapped cause")
+	}
+
+	wrapped := fmt.Errorf("repository: %w", err)
+	if !errors.Is(wrapped, gorm.ErrRecordNotFound) {
+		t.Error("Expected errors.Is to match the cause through fmt.Errorf")
+	}
+
+	var xErr *Error
+	if !errors.As(wrapped, &xErr) || xErr != err {
+		t.Error("Expected errors.As to find the xerror")
+	}
+
+	if !IsErrorCode(wrapped, ErrCodeNotFound) {
+		t.Error("Expected IsErrorCode to match the wrapping code")
+	}
+}
+
+func TestGetErrorCode(t *testing.T) {
+	tests := []struct {
+		name     string
+		err      error
+		expected string
+	}{
+		{
+			name:     "nil error",
+			err:      nil,
+			expected: "",
+		},
+		{
+			name:     "xerror",
+			err:      NewError(ErrCodeNotFound),
+			expected: ErrCodeNotFound,
+		},
+		{
+			name:     "standard error",
+			err:      errors.New("standard error"),
+			expected: ErrCodeInternalError,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			result := GetErrorCode(tc.err)
+			if result != tc.expected {
+				t.Errorf("Expected code %q, got %q", tc.expected, result)
+			}
+		})
+	}
+}
@@ -0,0 +1,102 @@
+package gin
+
+import (
+	"github.com/kurzgesagtz/xgo/xerror"
+	"github.com/kurzgesagtz/xgo/xlog"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gin-gonic/gin/render"
+	"go.uber.org/zap/zapcore"
+)
+
+// ErrorHandler renders the last *xerror.Error set with c.Error as JSON with the http status of its code.
+// Aggregated errors are rendered with their dominant code and every error, see xerror.MultiError.ToError.
+// Validation and binding errors are rendered as xerror.ErrCodeInvalidRequest, other errors which aren't xerror
+// as xerror.ErrCodeInternalError and panics as xerror.ErrCodeServerPanic.
+// The message is localized with xerror.DefaultCatalog by the Accept-Language header and the error is filtered
+// by xerror.PublicProfile when gin runs in release mode
+func ErrorHandler() gin.HandlerFunc {
+	return errorHandler(func(c *gin.Context, status int, err *xerror.Error) {
+		c.AbortWithStatusJSON(status, err)
+	})
+}
+
+// ProblemErrorHandler is like ErrorHandler but renders RFC 7807 application/problem+json with the request path as instance
+func ProblemErrorHandler() gin.HandlerFunc {
+	return errorHandler(func(c *gin.Context, status int, err *xerror.Error) {
+		c.Abort()
+		c.Header("Content-Type", xerror.ProblemContentType)
+		c.Render(status, render.JSON{Data: xerror.ToProblem(err, c.Request.URL.Path)})
+	})
+}
+
+type errorRenderer func(c *gin.Context, status int, err *xerror.Error)
+
+func errorHandler(renderer errorRenderer) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		defer func() {
+			if r := recover(); r != nil {
+				_ = c.Error(xerror.NewPanicError(r))
+				renderError(c, renderer)
+			}
+		}()
+		c.Next()
+		renderError(c, renderer)
+	}
+}
+
+func renderError(c *gin.Context, renderer errorRenderer) {
+	if len(c.Errors) == 0 {
+		return
+	}
+	err := lastError(c.Errors)
+	info := xerror.GetCodeInfo(err.Code)
+
+	logEvent(info.LogLevel).
+		Context(c).
+		Err(err).
+		Field("status", info.HTTPStatus).
+		Msg("request failed")
+
+	if c.Writer.Written() {
+		return
+	}
+	if gin.Mode() == gin.ReleaseMode {
+		err = err.Public()
+	}
+	ctx := c.Request.Context()
+	if xerror.LanguageFromContext(ctx) == nil {
+		ctx = xerror.WithAcceptLanguage(ctx, c.GetHeader("Accept-Language"))
+	}
+	err = xerror.DefaultCatalog.Localize(ctx, err)
+	renderer(c, info.HTTPStatus, err)
+}
+
+func lastError(errs []*gin.Error) *xerror.Error {
+	for i := len(errs) - 1; i >= 0; i-- {
+		if xErr := xerror.AsError(errs[i].Err); xErr != nil {
+			return xErr
+		}
+	}
+	last := errs[len(errs)-1]
+	if xErr := FromValidationErrors(last.Err); xErr != nil {
+		return xErr
+	}
+	if last.IsType(gin.ErrorTypeBind) {
+		return xerror.Wrap(last.Err, xerror.ErrCodeInvalidRequest)
+	}
+	return xerror.Wrap(last.Err, xerror.ErrCodeInternalError)
+}
+
+func logEvent(level zapcore.Level) *xlog.LogEvent {
+	switch level {
+	case zapcore.DebugLevel:
+		return xlog.Debug()
+	case zapcore.InfoLevel:
+		return xlog.Info()
+	case zapcore.WarnLevel:
+		return xlog.Warn()
+	default:
+		return xlog.Error()
+	}
+}
@@ -0,0 +1,258 @@
+package gin
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/kurzgesagtz/xgo/xerror"
+
+	"github.com/gin-gonic/gin"
+	"golang.org/x/text/language"
+)
+
+func performRequest(t *testing.T, mode string, handler gin.HandlerFunc) (*httptest.ResponseRecorder, map[string]any) {
+	t.Helper()
+	originalMode := gin.Mode()
+	gin.SetMode(mode)
+	defer gin.SetMode(originalMode)
+
+	r := gin.New()
+	r.Use(ErrorHandler())
+	r.GET("/test", handler)
+
+	w := httptest.NewRecorder()
+	req, err := http.NewRequest(http.MethodGet, "/test", nil)
+	if err != nil {
+		t.Fatalf("Failed to create request: %v", err)
+	}
+	r.ServeHTTP(w, req)
+
+	body := make(map[string]any)
+	if w.Body.Len() > 0 {
+		if err := json.Unmarshal(w.Body.Bytes(), &body); err != nil {
+			t.Fatalf("Failed to unmarshal body %s: %v", w.Body.String(), err)
+		}
+	}
+	return w, body
+}
+
+func TestErrorHandler(t *testing.T) {
+	tests := []struct {
+		name         string
+		handler      gin.HandlerFunc
+		expectStatus int
+		expectCode   string
+	}{
+		{
+			name: "no error",
+			handler: func(c *gin.Context) {
+				c.JSON(http.StatusOK, gin.H{"ok": true})
+			},
+			expectStatus: http.StatusOK,
+		},
+		{
+			name: "xerror",
+			handler: func(c *gin.Context) {
+				_ = c.Error(xerror.NewError(xerror.ErrCodeNotFound, xerror.WithMessage("user not found")))
+			},
+			expectStatus: http.StatusNotFound,
+			expectCode:   xerror.ErrCodeNotFound,
+		},
+		{
+			name: "last xerror wins",
+			handler: func(c *gin.Context) {
+				_ = c.Error(xerror.NewError(xerror.ErrCodeNotFound))
+				_ = c.Error(xerror.NewError(xerror.ErrCodePermissionDenied))
+				_ = c.Error(errors.New("plain error"))
+			},
+			expectStatus: http.StatusForbidden,
+			expectCode:   xerror.ErrCodePermissionDenied,
+		},
+		{
+			name: "plain error",
+			handler: func(c *gin.Context) {
+				_ = c.Error(errors.New("plain error"))
+			},
+			expectStatus: http.StatusInternalServerError,
+			expectCode:   xerror.ErrCodeInternalError,
+		},
+		{
+			name: "panic",
+			handler: func(c *gin.Context) {
+				panic("boom")
+			},
+			expectStatus: http.StatusInternalServerError,
+			expectCode:   xerror.ErrCodeServerPanic,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			w, body := performRequest(t, gin.TestMode, tc.handler)
+
+			if w.Code != tc.expectStatus {
+				t.Errorf("Expected status %d, got %d", tc.expectStatus, w.Code)
+			}
+
+			if tc.expectCode != "" && body["code"] != tc.expectCode {
+				t.Errorf("Expected code %s, got %v", tc.expectCode, body["code"])
+			}
+		})
+	}
+}
+
+func TestErrorHandler_Panic(t *testing.T) {
+	_, body := performRequest(t, gin.TestMode, func(c *gin.Context) {
+		panic("boom")
+	})
+
+	info, ok := body["info"].(map[string]any)
+	if !ok || info["panic"] != "boom" {
+		t.Errorf("Expected panic value in info, got %v", body["info"])
+	}
+
+	if _, ok := body["callers"]; !ok {
+		t.Error("Expected stack trace of the panic")
+	}
+
+	w, body := performRequest(t, gin.ReleaseMode, func(c *gin.Context) {
+		panic("boom")
+	})
+	if body["message"] != "Server panic" || strings.Contains(w.Body.String(), "boom") {
+		t.Errorf("Expected release mode to hide the panic value, got %s", w.Body.String())
+	}
+}
+
+func TestErrorHandler_ReleaseMode(t *testing.T) {
+	handler := func(c *gin.Context) {
+		_ = c.Error(xerror.Wrap(errors.New("sql: no rows"), xerror.ErrCodeNotFound))
+	}
+
+	// Internals are rendered outside release mode
+	_, body := performRequest(t, gin.TestMode, handler)
+	for _, key := range []string{"caller", "callers", "cause"} {
+		if _, ok := body[key]; !ok {
+			t.Errorf("Expected %s in test mode", key)
+		}
+	}
+
+	// and hidden in release mode
+	_, body = performRequest(t, gin.ReleaseMode, handler)
+	for _, key := range []string{"caller", "callers", "cause", "detail"} {
+		if _, ok := body[key]; ok {
+			t.Errorf("Expected %s to be hidden in release mode", key)
+		}
+	}
+
+	if body["code"] != xerror.ErrCodeNotFound {
+		t.Errorf("Expected code %s, got %v", xerror.ErrCodeNotFound, body["code"])
+	}
+}
+
+func TestErrorHandler_AlreadyWritten(t *testing.T) {
+	w, _ := performRequest(t, gin.TestMode, func(c *gin.Context) {
+		c.JSON(http.StatusAccepted, gin.H{"ok": true})
+		_ = c.Error(xerror.NewError(xerror.ErrCodeInternalError))
+	})
+
+	if w.Code != http.StatusAccepted {
+		t.Errorf("Expected the written status %d to be kept, got %d", http.StatusAccepted, w.Code)
+	}
+}
+
+func TestProblemErrorHandler(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	r.Use(ProblemErrorHandler())
+	r.GET("/users/:id", func(c *gin.Context) {
+		_ = c.Error(xerror.NewError(xerror.ErrCodeNotFound, xerror.WithMessage("user not found")))
+	})
+
+	w := httptest.NewRecorder()
+	req, err := http.NewRequest(http.MethodGet, "/users/1", nil)
+	if err != nil {
+		t.Fatalf("Failed to create request: %v", err)
+	}
+	r.ServeHTTP(w, req)
+
+	if w.Code != http.StatusNotFound {
+		t.Errorf("Expected status %d, got %d", http.StatusNotFound, w.Code)
+	}
+
+	if ct := w.Header().Get("Content-Type"); ct != xerror.ProblemContentType {
+		t.Errorf("Expected content type %s, got %s", xerror.ProblemContentType, ct)
+	}
+
+	var p xerror.Problem
+	if err := json.Unmarshal(w.Body.Bytes(), &p); err != nil {
+		t.Fatalf("Failed to unmarshal problem: %v", err)
+	}
+
+	if p.Instance != "/users/1" {
+		t.Errorf("Expected instance /users/1, got %s", p.Instance)
+	}
+
+	if p.Detail != "user not found" {
+		t.Errorf("Expected detail %q, got %q", "user not found", p.Detail)
+	}
+
+	if p.Extensions["code"] != xerror.ErrCodeNotFound {
+		t.Errorf("Expected code %s, got %v", xerror.ErrCodeNotFound, p.Extensions["code"])
+	}
+}
+
+func TestErrorHandler_Localize(t *testing.T) {
+	xerror.DefaultCatalog.Add(language.Thai, "TEST_LOCALIZED_CODE", "ไม่พบ{resource}")
+
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	r.Use(ErrorHandler())
+	r.GET("/test", func(c *gin.Context) {
+		_ = c.Error(xerror.NewError("TEST_LOCALIZED_CODE", xerror.WithMessage("user not found"), xerror.WithJSONInfo("resource", "ผู้ใช้")))
+	})
+
+	tests := []struct {
+		acceptLanguage string
+		expected       string
+	}{
+		{acceptLanguage: "th", expected: "ไม่พบผู้ใช้"},
+		{acceptLanguage: "en-US", expected: "user not found"},
+		{acceptLanguage: "", expected: "user not found"},
+	}
+
+	for _, tc := range tests {
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest(http.MethodGet, "/test", nil)
+		req.Header.Set("Accept-Language", tc.acceptLanguage)
+		r.ServeHTTP(w, req)
+
+		var body map[string]any
+		if err := json.Unmarshal(w.Body.Bytes(), &body); err != nil {
+			t.Fatalf("Failed to unmarshal body: %v", err)
+		}
+
+		if body["message"] != tc.expected {
+			t.Errorf("Expected message %q for %q, got %v", tc.expected, tc.acceptLanguage, body["message"])
+		}
+	}
+}
+
+func TestErrorHandler_MultiError(t *testing.T) {
+	w, body := performRequest(t, gin.ReleaseMode, func(c *gin.Context) {
+		_ = c.Error(xerror.Join(
+			xerror.NewError(xerror.ErrCodeNotFound),
+			xerror.NewError(xerror.ErrCodeInternalError),
+		))
+	})
+
+	if w.Code != http.StatusInternalServerError || body["code"] != xerror.ErrCodeInternalError {
+		t.Errorf("Expected the dominant code, got %d %v", w.Code, body["code"])
+	}
+	if errs, _ := body["errors"].([]any); len(errs) != 2 {
+		t.Errorf("Expected every error in the body, got %v", body["errors"])
+	}
+}
@@ -0,0 +1,73 @@
+package gin
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+
+	"github.com/kurzgesagtz/xgo/xerror"
+
+	"github.com/gin-gonic/gin/binding"
+	"github.com/go-playground/validator/v10"
+)
+
+// RegisterFieldNames makes v report fields by their json name, or form name, so violations match the request
+// payload, "Address.City" becomes "address.city". It changes FieldError.Field of every validation of v so it is
+// opt-in, call it before v validates any struct. Use RegisterGinFieldNames for the gin default validator
+func RegisterFieldNames(v *validator.Validate) {
+	v.RegisterTagNameFunc(requestFieldName)
+}
+
+// RegisterGinFieldNames calls RegisterFieldNames with the validator of gin binding, it is a no-op when
+// binding.Validator was replaced by a validator which isn't a *validator.Validate
+func RegisterGinFieldNames() {
+	if v, ok := binding.Validator.Engine().(*validator.Validate); ok {
+		RegisterFieldNames(v)
+	}
+}
+
+func requestFieldName(f reflect.StructField) string {
+	for _, key := range []string{"json", "form"} {
+		name, _, _ := strings.Cut(f.Tag.Get(key), ",")
+		if name != "" && name != "-" {
+			return name
+		}
+	}
+	return ""
+}
+
+// FromValidationErrors converts validator.ValidationErrors returned by gin binding into an
+// xerror.ErrCodeInvalidRequest xerror with one violation per failed field, nil is returned for other errors
+func FromValidationErrors(err error) *xerror.Error {
+	var vErrs validator.ValidationErrors
+	if !errors.As(err, &vErrs) {
+		return nil
+	}
+	violations := make([]xerror.FieldViolation, 0, len(vErrs))
+	for _, fe := range vErrs {
+		violations = append(violations, xerror.FieldViolation{
+			Field:   fieldPath(fe),
+			Rule:    fe.Tag(),
+			Message: violationMessage(fe),
+			Value:   fe.Value(),
+		})
+	}
+	return xerror.Wrap(err, xerror.ErrCodeInvalidRequest, xerror.WithFieldViolations(violations...))
+}
+
+// fieldPath removes the top level struct name from the namespace, "User.address.city" becomes "address.city"
+func fieldPath(fe validator.FieldError) string {
+	ns := fe.Namespace()
+	if i := strings.Index(ns, "."); i >= 0 {
+		return ns[i+1:]
+	}
+	return ns
+}
+
+func violationMessage(fe validator.FieldError) string {
+	if fe.Param() != "" {
+		return fmt.Sprintf("%s failed on the '%s=%s' rule", fe.Field(), fe.Tag(), fe.Param())
+	}
+	return fmt.Sprintf("%s failed on the '%s' rule", fe.Field(), fe.Tag())
+}
@@ -0,0 +1,134 @@
+package gin
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/kurzgesagtz/xgo/xerror"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gin-gonic/gin/binding"
+	"github.com/go-playground/validator/v10"
+)
+
+type testAddress struct {
+	City string `json:"city" binding:"required"`
+}
+
+type testUser struct {
+	Name     string      `json:"name" binding:"required"`
+	Age      int         `json:"age" binding:"gte=18"`
+	Address  testAddress `json:"address"`
+	Nickname string      `json:"-" binding:"required"`
+	Page     int         `form:"page" binding:"gte=1"`
+}
+
+// newTestValidator returns a validator reading the binding tags like the gin default validator
+func newTestValidator(fieldNames bool) *validator.Validate {
+	v := validator.New()
+	v.SetTagName("binding")
+	if fieldNames {
+		RegisterFieldNames(v)
+	}
+	return v
+}
+
+func TestFromValidationErrors_GoNames(t *testing.T) {
+	err := FromValidationErrors(newTestValidator(false).Struct(&testUser{Age: 17}))
+	if err == nil {
+		t.Fatal("Expected xerror")
+	}
+
+	// Without RegisterFieldNames the Go names are reported
+	expected := map[string]string{
+		"Name":         "required",
+		"Age":          "gte",
+		"Address.City": "required",
+		"Nickname":     "required",
+		"Page":         "gte",
+	}
+	if len(err.Violations) != len(expected) {
+		t.Fatalf("Expected %d violations, got %+v", len(expected), err.Violations)
+	}
+	for _, v := range err.Violations {
+		if expected[v.Field] != v.Rule {
+			t.Errorf("Unexpected violation %+v", v)
+		}
+	}
+}
+
+func TestFromValidationErrors(t *testing.T) {
+	bindErr := newTestValidator(true).Struct(&testUser{Age: 17})
+	if bindErr == nil {
+		t.Fatal("Expected validation error")
+	}
+
+	err := FromValidationErrors(bindErr)
+	if err == nil {
+		t.Fatal("Expected xerror")
+	}
+
+	if err.Code != xerror.ErrCodeInvalidRequest {
+		t.Errorf("Expected code %s, got %s", xerror.ErrCodeInvalidRequest, err.Code)
+	}
+
+	expected := map[string]string{
+		"name":         "required",
+		"age":          "gte",
+		"address.city": "required",
+		"Nickname":     "required",
+		"page":         "gte",
+	}
+	if len(err.Violations) != len(expected) {
+		t.Fatalf("Expected %d violations, got %+v", len(expected), err.Violations)
+	}
+	for _, v := range err.Violations {
+		if expected[v.Field] != v.Rule {
+			t.Errorf("Unexpected violation %+v", v)
+		}
+		if v.Field == "age" {
+			if v.Value != 17 {
+				t.Errorf("Expected rejected value 17, got %v", v.Value)
+			}
+			if !strings.Contains(v.Message, "gte=18") {
+				t.Errorf("Expected message with rule param, got %s", v.Message)
+			}
+		}
+	}
+
+	// Other errors aren't converted
+	if err := FromValidationErrors(errors.New("standard error")); err != nil {
+		t.Errorf("Expected nil for standard error, got %v", err)
+	}
+}
+
+func TestErrorHandler_Binding(t *testing.T) {
+	// Validation errors
+	w, body := performRequest(t, gin.TestMode, func(c *gin.Context) {
+		_ = c.Error(binding.Validator.ValidateStruct(&testUser{Name: "a", Age: 20, Nickname: "b", Page: 1}))
+	})
+
+	if w.Code != http.StatusBadRequest {
+		t.Errorf("Expected status %d, got %d", http.StatusBadRequest, w.Code)
+	}
+
+	violations, ok := body["violations"].([]any)
+	if !ok || len(violations) != 1 {
+		t.Errorf("Expected 1 violation, got %v", body["violations"])
+	}
+
+	// Other binding errors
+	w, body = performRequest(t, gin.TestMode, func(c *gin.Context) {
+		_ = c.Error(errors.New("invalid character")).SetType(gin.ErrorTypeBind)
+	})
+
+	if w.Code != http.StatusBadRequest {
+		t.Errorf("Expected status %d, got %d", http.StatusBadRequest, w.Code)
+	}
+
+	if body["code"] != xerror.ErrCodeInvalidRequest {
+		t.Errorf("Expected code %s, got %v", xerror.ErrCodeInvalidRequest, body["code"])
+	}
+}
@@ -0,0 +1,71 @@
+package grpc
+
+import (
+	"context"
+	"errors"
+
+	"github.com/kurzgesagtz/xgo/xerror"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+var _ StreamClientErrorTranslator = DefaultErrorTranslator
+
+// DefaultErrorTranslator rebuilds xerror from grpc status details and trailer,
+// context cancellation and deadline are mapped to client request xerror codes
+func DefaultErrorTranslator(err error, trailer metadata.MD) error {
+	return ContextErrorTranslator(context.Background())(err, trailer)
+}
+
+// ContextErrorTranslator is DefaultErrorTranslator which appends the local service to the provenance
+// of xerror rebuilt from grpc status, the trace id is taken from ctx. The caller of the local hop is left empty
+// since the call site of the application is not known to the interceptors
+func ContextErrorTranslator(ctx context.Context) StreamClientErrorTranslator {
+	return func(err error, trailer metadata.MD) error {
+		if err == nil {
+			return nil
+		}
+		var xErr *xerror.Error
+		if errors.As(err, &xErr) {
+			return err
+		}
+		if errors.Is(err, context.Canceled) {
+			return xerror.Wrap(err, xerror.ErrCodeClientRequestCanceled)
+		}
+		if errors.Is(err, context.DeadlineExceeded) {
+			return xerror.Wrap(err, xerror.ErrCodeClientRequestDeadlineExceed)
+		}
+		st, ok := status.FromError(err)
+		if !ok {
+			return xerror.Wrap(err, xerror.ErrCodeServiceInternalError)
+		}
+		return xerror.ReceiveAt(ctx, FromStatus(st, trailer), "")
+	}
+}
+
+// UnaryClientInterceptor translates errors of unary calls with ContextErrorTranslator
+func UnaryClientInterceptor() grpc.UnaryClientInterceptor {
+	return func(ctx context.Context, method string, req, reply any, cc *grpc.ClientConn, invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
+		var trailer metadata.MD
+		err := invoker(ctx, method, req, reply, cc, append(opts, grpc.Trailer(&trailer))...)
+		if err != nil {
+			return ContextErrorTranslator(ctx)(err, trailer)
+		}
+		return nil
+	}
+}
+
+// StreamClientInterceptor wraps client streams with StreamClientWrapper using ContextErrorTranslator
+func StreamClientInterceptor() grpc.StreamClientInterceptor {
+	return func(ctx context.Context, desc *grpc.StreamDesc, cc *grpc.ClientConn, method string, streamer grpc.Streamer, opts ...grpc.CallOption) (grpc.ClientStream, error) {
+		cs, err := streamer(ctx, desc, cc, method, opts...)
+		if err != nil {
+			return nil, ContextErrorTranslator(ctx)(err, nil)
+		}
+		sw := NewStreamClientWrapper(cs, desc)
+		sw.SetErrorTranslator(ContextErrorTranslator(ctx))
+		return sw, nil
+	}
+}
@@ -0,0 +1,232 @@
+package grpc
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/kurzgesagtz/xgo/xerror"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+func TestDefaultErrorTranslator(t *testing.T) {
+	remote := xerror.NewError(xerror.ErrCodeAlreadyExists, xerror.WithMessage("duplicated"))
+	remote.AppName = "remote-service"
+	local := xerror.NewError(xerror.ErrCodeNotFound)
+
+	tests := []struct {
+		name     string
+		err      error
+		expected string
+	}{
+		{
+			name:     "nil error",
+			err:      nil,
+			expected: "",
+		},
+		{
+			name:     "xerror is kept",
+			err:      local,
+			expected: xerror.ErrCodeNotFound,
+		},
+		{
+			name:     "context canceled",
+			err:      fmt.Errorf("call: %w", context.Canceled),
+			expected: xerror.ErrCodeClientRequestCanceled,
+		},
+		{
+			name:     "context deadline exceeded",
+			err:      context.DeadlineExceeded,
+			expected: xerror.ErrCodeClientRequestDeadlineExceed,
+		},
+		{
+			name:     "status canceled",
+			err:      status.Error(codes.Canceled, "context canceled"),
+			expected: xerror.ErrCodeClientRequestCanceled,
+		},
+		{
+			name:     "status deadline exceeded",
+			err:      status.Error(codes.DeadlineExceeded, "deadline exceeded"),
+			expected: xerror.ErrCodeClientRequestDeadlineExceed,
+		},
+		{
+			name:     "status with details",
+			err:      ToStatus(remote).Err(),
+			expected: xerror.ErrCodeAlreadyExists,
+		},
+		{
+			name:     "non status error",
+			err:      errors.New("plain error"),
+			expected: xerror.ErrCodeServiceInternalError,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			err := DefaultErrorTranslator(tc.err, nil)
+			if tc.expected == "" {
+				if err != nil {
+					t.Errorf("Expected nil error, got %v", err)
+				}
+				return
+			}
+			if !xerror.IsErrorCode(err, tc.expected) {
+				t.Errorf("Expected code %s, got %v", tc.expected, err)
+			}
+		})
+	}
+
+	// Existing xerror is returned as is
+	if err := DefaultErrorTranslator(local, nil); err != local {
+		t.Errorf("Expected the same xerror, got %v", err)
+	}
+}
+
+func TestUnaryClientInterceptor(t *testing.T) {
+	interceptor := UnaryClientInterceptor()
+	remote := xerror.NewError(xerror.ErrCodeNotFound)
+
+	// Trailer is collected through the call option
+	invoker := func(ctx context.Context, method string, req, reply any, cc *grpc.ClientConn, opts ...grpc.CallOption) error {
+		for _, opt := range opts {
+			if o, ok := opt.(grpc.TrailerCallOption); ok {
+				*o.TrailerAddr = metadata.Pairs(AppNameTrailerKey, "remote-service")
+			}
+		}
+		return status.Error(codes.NotFound, "not found")
+	}
+
+	err := interceptor(context.Background(), "/test.Service/Method", nil, nil, nil, invoker)
+
+	var xErr *xerror.Error
+	if !errors.As(err, &xErr) {
+		t.Fatalf("Expected xerror, got %v", err)
+	}
+
+	if xErr.Code != remote.Code {
+		t.Errorf("Expected code %s, got %s", remote.Code, xErr.Code)
+	}
+
+	if xErr.AppName != "remote-service" {
+		t.Errorf("Expected app name from trailer remote-service, got %s", xErr.AppName)
+	}
+
+	// No error
+	err = interceptor(context.Background(), "/test.Service/Method", nil, nil, nil,
+		func(ctx context.Context, method string, req, reply any, cc *grpc.ClientConn, opts ...grpc.CallOption) error {
+			return nil
+		})
+	if err != nil {
+		t.Errorf("Expected no error, got %v", err)
+	}
+}
+
+func TestUnaryClientInterceptor_Provenance(t *testing.T) {
+	interceptor := UnaryClientInterceptor()
+
+	// user-service created the error, order-service received and re-raised it
+	remote := xerror.NewError(xerror.ErrCodeNotFound)
+	remote.AppName = "user-service"
+	remote.Provenance = []xerror.Hop{{AppName: "user-service"}, {AppName: "order-service"}}
+
+	err := interceptor(context.Background(), "/test.Service/Method", nil, nil, nil,
+		func(ctx context.Context, method string, req, reply any, cc *grpc.ClientConn, opts ...grpc.CallOption) error {
+			return ToStatus(remote).Err()
+		})
+
+	var xErr *xerror.Error
+	if !errors.As(err, &xErr) {
+		t.Fatalf("Expected xerror, got %v", err)
+	}
+
+	hops := xErr.Provenance
+	if len(hops) != 3 || hops[0].AppName != "user-service" || hops[1].AppName != "order-service" {
+		t.Fatalf("Expected remote provenance followed by the local hop, got %v", hops)
+	}
+
+	if _, ok := xErr.Info[ProvenanceMetadataKey]; ok {
+		t.Error("Expected provenance not to leak into info")
+	}
+}
+
+func TestStreamClientInterceptor(t *testing.T) {
+	interceptor := StreamClientInterceptor()
+	desc := &grpc.StreamDesc{ServerStreams: true}
+
+	// Stream errors are translated by the wrapper
+	mockStream := &mockClientStream{
+		recvMsgErr: status.Error(codes.PermissionDenied, "denied"),
+	}
+	cs, err := interceptor(context.Background(), desc, nil, "/test.Service/Stream",
+		func(ctx context.Context, desc *grpc.StreamDesc, cc *grpc.ClientConn, method string, opts ...grpc.CallOption) (grpc.ClientStream, error) {
+			return mockStream, nil
+		})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if _, ok := cs.(StreamClientWrapper); !ok {
+		t.Error("Expected stream to be wrapped with StreamClientWrapper")
+	}
+
+	if err := cs.RecvMsg(nil); !xerror.IsErrorCode(err, xerror.ErrCodePermissionDenied) {
+		t.Errorf("Expected code %s, got %v", xerror.ErrCodePermissionDenied, err)
+	}
+
+	// Errors creating the stream are translated too
+	_, err = interceptor(context.Background(), desc, nil, "/test.Service/Stream",
+		func(ctx context.Context, desc *grpc.StreamDesc, cc *grpc.ClientConn, method string, opts ...grpc.CallOption) (grpc.ClientStream, error) {
+			return nil, status.Error(codes.DeadlineExceeded, "deadline")
+		})
+	if !xerror.IsErrorCode(err, xerror.ErrCodeClientRequestDeadlineExceed) {
+		t.Errorf("Expected code %s, got %v", xerror.ErrCodeClientRequestDeadlineExceed, err)
+	}
+}
+
+func TestProvenance_WrapAndForward(t *testing.T) {
+	defer xerror.SetAppName(xerror.AppName())
+	server := UnaryServerInterceptor()
+	client := UnaryClientInterceptor()
+	info := &grpc.UnaryServerInfo{FullMethod: "/test.Service/Method"}
+
+	// call makes the client interceptor of the local app receive the error returned by the handler of a peer
+	call := func(handler grpc.UnaryHandler) error {
+		_, sErr := server(context.Background(), nil, info, handler)
+		return client(context.Background(), "/test.Service/Method", nil, nil, nil,
+			func(ctx context.Context, method string, req, reply any, cc *grpc.ClientConn, opts ...grpc.CallOption) error {
+				return sErr
+			})
+	}
+
+	xerror.SetAppName("user-service")
+	origin := xerror.NewError(xerror.ErrCodeNotFound)
+
+	xerror.SetAppName("order-service")
+	received := call(func(ctx context.Context, req any) (any, error) {
+		return nil, origin
+	})
+
+	xerror.SetAppName("gateway")
+	err := call(func(ctx context.Context, req any) (any, error) {
+		// order-service re-raises the error it received with its own code
+		return nil, xerror.Wrap(received, xerror.ErrCodeServiceInternalError)
+	})
+
+	if !xerror.IsErrorCode(err, xerror.ErrCodeServiceInternalError) {
+		t.Fatalf("Expected code %s, got %v", xerror.ErrCodeServiceInternalError, err)
+	}
+	if s := xerror.FormatProvenance(xerror.GetProvenance(err)); s != "user-service -> order-service -> gateway" {
+		t.Errorf("Expected the provenance to survive the wrap, got %q", s)
+	}
+	// The interceptors don't know the call site of the application, the local hops have no caller
+	for _, hop := range xerror.GetProvenance(err)[1:] {
+		if hop.Caller != "" {
+			t.Errorf("Expected no caller for the hop of %s, got %s", hop.AppName, hop.Caller)
+		}
+	}
+}
@@ -0,0 +1,53 @@
+package grpc
+
+import (
+	"context"
+
+	"github.com/kurzgesagtz/xgo/xerror"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+)
+
+// UnaryServerInterceptor converts *xerror.Error returned by handlers into grpc status, aggregated errors use
+// their dominant code, panics are recovered as xerror.ErrCodeServerPanic
+func UnaryServerInterceptor() grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (resp any, err error) {
+		defer func() {
+			if r := recover(); r != nil {
+				err = xerror.NewPanicError(r)
+			}
+			err = serverError(err, func(md metadata.MD) {
+				_ = grpc.SetTrailer(ctx, md)
+			})
+		}()
+		return handler(ctx, req)
+	}
+}
+
+// StreamServerInterceptor is the stream version of UnaryServerInterceptor
+func StreamServerInterceptor() grpc.StreamServerInterceptor {
+	return func(srv any, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) (err error) {
+		defer func() {
+			if r := recover(); r != nil {
+				err = xerror.NewPanicError(r)
+			}
+			err = serverError(err, ss.SetTrailer)
+		}()
+		return handler(srv, ss)
+	}
+}
+
+func serverError(err error, setTrailer func(md metadata.MD)) error {
+	if err == nil {
+		return nil
+	}
+	xErr := xerror.AsError(err)
+	if xErr == nil {
+		return err
+	}
+	if md := trailerOf(xErr); md != nil {
+		setTrailer(md)
+	}
+	return ToStatus(xErr).Err()
+}
@@ -0,0 +1,182 @@
+package grpc
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/kurzgesagtz/xgo/xerror"
+
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+// mockServerStream is a mock implementation of grpc.ServerStream for testing
+type mockServerStream struct {
+	grpc.ServerStream
+	ctx        context.Context
+	trailer    metadata.MD
+	sendMsgErr error
+	recvMsgErr error
+}
+
+func (m *mockServerStream) Context() context.Context {
+	if m.ctx == nil {
+		return context.Background()
+	}
+	return m.ctx
+}
+
+func (m *mockServerStream) SetTrailer(md metadata.MD) {
+	m.trailer = metadata.Join(m.trailer, md)
+}
+
+func (m *mockServerStream) SendMsg(msg any) error {
+	return m.sendMsgErr
+}
+
+func (m *mockServerStream) RecvMsg(msg any) error {
+	return m.recvMsgErr
+}
+
+func statusReason(t *testing.T, err error) string {
+	t.Helper()
+	st, ok := status.FromError(err)
+	if !ok {
+		t.Fatalf("Expected grpc status error, got %v", err)
+	}
+	for _, d := range st.Details() {
+		if info, ok := d.(*errdetails.ErrorInfo); ok {
+			return info.Reason
+		}
+	}
+	return ""
+}
+
+func TestUnaryServerInterceptor(t *testing.T) {
+	interceptor := UnaryServerInterceptor()
+	info := &grpc.UnaryServerInfo{FullMethod: "/test.Service/Method"}
+	plainErr := errors.New("plain error")
+
+	tests := []struct {
+		name       string
+		handler    grpc.UnaryHandler
+		expectCode codes.Code
+		reason     string
+		expectErr  error
+	}{
+		{
+			name: "no error",
+			handler: func(ctx context.Context, req any) (any, error) {
+				return "ok", nil
+			},
+			expectCode: codes.OK,
+		},
+		{
+			name: "xerror",
+			handler: func(ctx context.Context, req any) (any, error) {
+				return nil, xerror.NewError(xerror.ErrCodePermissionDenied)
+			},
+			expectCode: codes.PermissionDenied,
+			reason:     xerror.ErrCodePermissionDenied,
+		},
+		{
+			name: "plain error is passed through",
+			handler: func(ctx context.Context, req any) (any, error) {
+				return nil, plainErr
+			},
+			expectErr: plainErr,
+		},
+		{
+			name: "panic",
+			handler: func(ctx context.Context, req any) (any, error) {
+				panic("boom")
+			},
+			expectCode: codes.Internal,
+			reason:     xerror.ErrCodeServerPanic,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			resp, err := interceptor(context.Background(), nil, info, tc.handler)
+
+			if tc.expectErr != nil {
+				if err != tc.expectErr {
+					t.Errorf("Expected error %v, got %v", tc.expectErr, err)
+				}
+				return
+			}
+
+			if tc.expectCode == codes.OK {
+				if err != nil || resp != "ok" {
+					t.Errorf("Expected ok response, got %v, %v", resp, err)
+				}
+				return
+			}
+
+			if status.Code(err) != tc.expectCode {
+				t.Errorf("Expected code %v, got %v", tc.expectCode, status.Code(err))
+			}
+
+			if reason := statusReason(t, err); reason != tc.reason {
+				t.Errorf("Expected reason %s, got %s", tc.reason, reason)
+			}
+		})
+	}
+}
+
+func TestStreamServerInterceptor(t *testing.T) {
+	interceptor := StreamServerInterceptor()
+	info := &grpc.StreamServerInfo{FullMethod: "/test.Service/Stream"}
+
+	// xerror is converted and the app name is sent in the trailer
+	ss := &mockServerStream{}
+	err := interceptor(nil, ss, info, func(srv any, stream grpc.ServerStream) error {
+		xErr := xerror.NewError(xerror.ErrCodeNotFound)
+		xErr.AppName = "stream-service"
+		return xErr
+	})
+
+	if status.Code(err) != codes.NotFound {
+		t.Errorf("Expected code %v, got %v", codes.NotFound, status.Code(err))
+	}
+
+	if v := ss.trailer.Get(AppNameTrailerKey); len(v) != 1 || v[0] != "stream-service" {
+		t.Errorf("Expected trailer %s=stream-service, got %v", AppNameTrailerKey, v)
+	}
+
+	// panic is recovered
+	err = interceptor(nil, &mockServerStream{}, info, func(srv any, stream grpc.ServerStream) error {
+		panic("boom")
+	})
+
+	if reason := statusReason(t, err); reason != xerror.ErrCodeServerPanic {
+		t.Errorf("Expected reason %s, got %s", xerror.ErrCodeServerPanic, reason)
+	}
+}
+
+func TestUnaryServerInterceptor_MultiError(t *testing.T) {
+	interceptor := UnaryServerInterceptor()
+	info := &grpc.UnaryServerInfo{FullMethod: "/test.Service/Method"}
+
+	_, err := interceptor(context.Background(), nil, info, func(ctx context.Context, req any) (any, error) {
+		return nil, xerror.Join(xerror.NewError(xerror.ErrCodeNotFound), xerror.NewError(xerror.ErrCodeInternalError))
+	})
+
+	if status.Code(err) != codes.Internal || statusReason(t, err) != xerror.ErrCodeInternalError {
+		t.Errorf("Expected the dominant code, got %v", err)
+	}
+
+	// Every error is carried along with the dominant code
+	children := FromStatus(status.Convert(err), nil).Errors
+	if len(children) != 2 || children[0].Code != xerror.ErrCodeNotFound || children[1].Code != xerror.ErrCodeInternalError {
+		t.Fatalf("Expected the aggregated errors, got %v", children)
+	}
+	if children[0].Caller != "" || children[0].Callers != nil {
+		t.Errorf("Expected no caller without DebugInfo, got %s", children[0].Caller)
+	}
+}
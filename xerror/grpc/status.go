@@ -0,0 +1,221 @@
+package grpc
+
+import (
+	"encoding/json"
+	"fmt"
+	"sync/atomic"
+
+	"github.com/kurzgesagtz/xgo/xerror"
+	"github.com/kurzgesagtz/xgo/xerror/xerrorpb"
+
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/protoadapt"
+)
+
+// AppNameTrailerKey is the trailer key carrying the app name of the service which returned the xerror
+const AppNameTrailerKey = "x-app-name"
+
+// ProvenanceMetadataKey is the errdetails.ErrorInfo metadata key carrying the provenance of the xerror
+const ProvenanceMetadataKey = "xerror_provenance"
+
+// ErrorsMetadataKey is the errdetails.ErrorInfo metadata key carrying the aggregated errors of the xerror,
+// see xerror.MultiError.ToError
+const ErrorsMetadataKey = "xerror_errors"
+
+var fullDetails atomic.Bool
+
+var debugInfo atomic.Bool
+
+// SetDebugInfo makes ToStatus attach the Caller of xerror as errdetails.DebugInfo, it exposes server source paths
+// so enable it only between internal services
+func SetDebugInfo(enabled bool) {
+	debugInfo.Store(enabled)
+}
+
+// SetFullDetails makes ToStatus attach the whole xerror as xerrorpb.Error along with the standard details, it includes
+// the stack and the cause chain so enable it only between internal services. FromStatus always reads it when present
+func SetFullDetails(enabled bool) {
+	fullDetails.Store(enabled)
+}
+
+// ToStatus converts xerror to grpc status, the code is mapped with the xerror registry.
+// Code, AppName, Info, the provenance of the chain, see xerror.GetProvenance, and the aggregated Errors are carried
+// by errdetails.ErrorInfo, Violations by errdetails.BadRequest without their rejected value and Caller
+// by errdetails.DebugInfo when SetDebugInfo is enabled. Errors are filtered like the standard details,
+// without stack, cause or rejected values, and with their caller only when SetDebugInfo is enabled
+func ToStatus(err *xerror.Error) *status.Status {
+	st := status.New(xerror.GRPCCode(err.Code), err.Message)
+
+	md := make(map[string]string, len(err.Info)+1)
+	for k, v := range err.Info {
+		md[k] = encodeInfoValue(v)
+	}
+	if provenance := xerror.GetProvenance(err); len(provenance) > 0 {
+		md[ProvenanceMetadataKey] = encodeInfoValue(provenance)
+	}
+	if len(err.Errors) > 0 {
+		profile := xerror.Profile{Caller: debugInfo.Load(), AppName: true}
+		children := make([]*xerror.Error, len(err.Errors))
+		for i, e := range err.Errors {
+			children[i] = profile.Apply(e)
+		}
+		md[ErrorsMetadataKey] = encodeInfoValue(children)
+	}
+	details := []protoadapt.MessageV1{
+		&errdetails.ErrorInfo{
+			Reason:   err.Code,
+			Domain:   err.AppName,
+			Metadata: md,
+		},
+	}
+	if len(err.Violations) > 0 {
+		br := &errdetails.BadRequest{}
+		for _, v := range err.Violations {
+			br.FieldViolations = append(br.FieldViolations, &errdetails.BadRequest_FieldViolation{
+				Field:       v.Field,
+				Description: v.Message,
+				Reason:      v.Rule,
+			})
+		}
+		details = append(details, br)
+	}
+	if err.Caller != "" && debugInfo.Load() {
+		details = append(details, &errdetails.DebugInfo{
+			Detail: err.Caller,
+		})
+	}
+
+	if fullDetails.Load() {
+		if pb, pErr := xerror.ToProto(err); pErr == nil {
+			details = append(details, protoadapt.MessageV1Of(pb))
+		}
+	}
+
+	if stWithDetails, dErr := st.WithDetails(details...); dErr == nil {
+		return stWithDetails
+	}
+	return st
+}
+
+// FromStatus rebuilds xerror from grpc status created by ToStatus, trailer is used as fallback for the app name.
+// Status without ErrorInfo is mapped by its grpc code. Caller and Detail are the remote caller of errdetails.DebugInfo,
+// they are empty without it. The whole xerror is restored when the status carries
+// xerrorpb.Error, see SetFullDetails
+func FromStatus(st *status.Status, trailer metadata.MD) *xerror.Error {
+	for _, d := range st.Details() {
+		if pb, ok := d.(*xerrorpb.Error); ok {
+			err := xerror.FromProto(pb)
+			if err.Cause == nil {
+				err.Cause = st.Err()
+			}
+			return err
+		}
+	}
+	code := codeFromGRPC(st.Code())
+	appName := ""
+	caller := ""
+	var info map[string]any
+	var violations []xerror.FieldViolation
+	var provenance []xerror.Hop
+	var children []*xerror.Error
+	for _, d := range st.Details() {
+		switch v := d.(type) {
+		case *errdetails.ErrorInfo:
+			code = v.Reason
+			appName = v.Domain
+			for k, val := range v.Metadata {
+				if k == ProvenanceMetadataKey && json.Unmarshal([]byte(val), &provenance) == nil {
+					continue
+				}
+				if k == ErrorsMetadataKey && json.Unmarshal([]byte(val), &children) == nil {
+					continue
+				}
+				if info == nil {
+					info = make(map[string]any, len(v.Metadata))
+				}
+				info[k] = decodeInfoValue(val)
+			}
+		case *errdetails.DebugInfo:
+			caller = v.Detail
+		case *errdetails.BadRequest:
+			for _, fv := range v.FieldViolations {
+				violations = append(violations, xerror.FieldViolation{
+					Field:   fv.Field,
+					Rule:    fv.Reason,
+					Message: fv.Description,
+				})
+			}
+		}
+	}
+	if appName == "" {
+		if v := trailer.Get(AppNameTrailerKey); len(v) > 0 {
+			appName = v[0]
+		}
+	}
+
+	// the local caller and stack don't describe a remote error, Caller and Detail are only known from DebugInfo
+	err := &xerror.Error{
+		Code:       code,
+		Message:    st.Message(),
+		Caller:     caller,
+		Detail:     caller,
+		AppName:    appName,
+		Timestamp:  xerror.TimestampNow(),
+		Info:       info,
+		Violations: violations,
+		Provenance: provenance,
+		Errors:     children,
+		Cause:      st.Err(),
+	}
+	if info, ok := xerror.LookupCode(code); ok && err.Message == "" {
+		err.Message = info.Message
+	}
+	return err
+}
+
+var grpcCodeToErrCode = map[codes.Code]string{
+	codes.Canceled:          xerror.ErrCodeClientRequestCanceled,
+	codes.DeadlineExceeded:  xerror.ErrCodeClientRequestDeadlineExceed,
+	codes.InvalidArgument:   xerror.ErrCodeInvalidRequest,
+	codes.NotFound:          xerror.ErrCodeNotFound,
+	codes.AlreadyExists:     xerror.ErrCodeAlreadyExists,
+	codes.PermissionDenied:  xerror.ErrCodePermissionDenied,
+	codes.Unauthenticated:   xerror.ErrCodeUnauthorized,
+	codes.ResourceExhausted: xerror.ErrCodeRateLimitExceeded,
+	codes.Unimplemented:     xerror.ErrCodeNotImplement,
+}
+
+func codeFromGRPC(c codes.Code) string {
+	if code, ok := grpcCodeToErrCode[c]; ok {
+		return code
+	}
+	return xerror.ErrCodeServiceInternalError
+}
+
+// trailerOf returns the trailer which is sent along with the status of err
+func trailerOf(err *xerror.Error) metadata.MD {
+	if err.AppName == "" {
+		return nil
+	}
+	return metadata.Pairs(AppNameTrailerKey, err.AppName)
+}
+
+// encodeInfoValue encodes info value as JSON so FromStatus can restore its type
+func encodeInfoValue(v any) string {
+	b, err := json.Marshal(v)
+	if err != nil {
+		return fmt.Sprintf("%v", v)
+	}
+	return string(b)
+}
+
+func decodeInfoValue(s string) any {
+	var v any
+	if err := json.Unmarshal([]byte(s), &v); err != nil {
+		return s
+	}
+	return v
+}
@@ -0,0 +1,222 @@
+package grpc
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/kurzgesagtz/xgo/xerror"
+
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+func TestToStatus(t *testing.T) {
+	xErr := xerror.NewError(xerror.ErrCodeNotFound,
+		xerror.WithMessage("user not found"),
+		xerror.WithJSONInfo("user_id", "u-1"),
+		xerror.WithJSONInfo("attempt", 2),
+	)
+	xErr.AppName = "user-service"
+
+	st := ToStatus(xErr)
+
+	if st.Code() != codes.NotFound {
+		t.Errorf("Expected code %v, got %v", codes.NotFound, st.Code())
+	}
+
+	if st.Message() != "user not found" {
+		t.Errorf("Expected message %q, got %q", "user not found", st.Message())
+	}
+
+	var errorInfo *errdetails.ErrorInfo
+	var debugInfo *errdetails.DebugInfo
+	for _, d := range st.Details() {
+		switch v := d.(type) {
+		case *errdetails.ErrorInfo:
+			errorInfo = v
+		case *errdetails.DebugInfo:
+			debugInfo = v
+		}
+	}
+
+	if errorInfo == nil {
+		t.Fatal("Expected ErrorInfo detail")
+	}
+
+	if errorInfo.Reason != xerror.ErrCodeNotFound {
+		t.Errorf("Expected reason %s, got %s", xerror.ErrCodeNotFound, errorInfo.Reason)
+	}
+
+	if errorInfo.Domain != "user-service" {
+		t.Errorf("Expected domain user-service, got %s", errorInfo.Domain)
+	}
+
+	if errorInfo.Metadata["user_id"] != `"u-1"` {
+		t.Errorf("Expected user_id metadata \"u-1\", got %s", errorInfo.Metadata["user_id"])
+	}
+
+	if errorInfo.Metadata["attempt"] != "2" {
+		t.Errorf("Expected attempt metadata 2, got %s", errorInfo.Metadata["attempt"])
+	}
+
+	if debugInfo != nil {
+		t.Errorf("Expected no DebugInfo by default, got %v", debugInfo)
+	}
+
+	SetDebugInfo(true)
+	defer SetDebugInfo(false)
+	debugInfo = nil
+	for _, d := range ToStatus(xErr).Details() {
+		if v, ok := d.(*errdetails.DebugInfo); ok {
+			debugInfo = v
+		}
+	}
+	if debugInfo == nil || debugInfo.Detail != xErr.Caller {
+		t.Errorf("Expected DebugInfo with caller %s, got %v", xErr.Caller, debugInfo)
+	}
+}
+
+func TestTrailerOf(t *testing.T) {
+	if md := trailerOf(&xerror.Error{}); md != nil {
+		t.Errorf("Expected nil trailer without app name, got %v", md)
+	}
+
+	md := trailerOf(&xerror.Error{AppName: "user-service"})
+	if v := md.Get(AppNameTrailerKey); len(v) != 1 || v[0] != "user-service" {
+		t.Errorf("Expected trailer %s=user-service, got %v", AppNameTrailerKey, v)
+	}
+}
+
+func TestFromStatus(t *testing.T) {
+	// Round trip through ToStatus
+	xErr := xerror.NewError(xerror.ErrCodeNotFound,
+		xerror.WithMessage("user not found"),
+		xerror.WithJSONInfo("user_id", "u-1"),
+		xerror.WithJSONInfo("attempt", 2),
+	)
+	xErr.AppName = "user-service"
+
+	SetDebugInfo(true)
+	defer SetDebugInfo(false)
+	result := FromStatus(ToStatus(xErr), nil)
+
+	if result.Code != xerror.ErrCodeNotFound {
+		t.Errorf("Expected code %s, got %s", xerror.ErrCodeNotFound, result.Code)
+	}
+
+	if result.Message != "user not found" {
+		t.Errorf("Expected message %q, got %q", "user not found", result.Message)
+	}
+
+	if result.AppName != "user-service" {
+		t.Errorf("Expected app name user-service, got %s", result.AppName)
+	}
+
+	if result.Caller != xErr.Caller || result.Detail != xErr.Caller || result.Callers != nil {
+		t.Errorf("Expected remote caller %s without local stack, got %s %s", xErr.Caller, result.Caller, result.Detail)
+	}
+
+	if result.Info["user_id"] != "u-1" {
+		t.Errorf("Expected info user_id u-1, got %v", result.Info["user_id"])
+	}
+
+	if result.Info["attempt"] != float64(2) {
+		t.Errorf("Expected info attempt 2, got %v", result.Info["attempt"])
+	}
+
+	if status.Code(result) != codes.NotFound {
+		t.Errorf("Expected status to be reachable from the cause, got %v", status.Code(result))
+	}
+
+	// Status without details is mapped by grpc code, app name comes from trailer
+	result = FromStatus(status.New(codes.Unavailable, "connection refused"), metadata.Pairs(AppNameTrailerKey, "other-service"))
+
+	if result.Code != xerror.ErrCodeServiceInternalError {
+		t.Errorf("Expected code %s, got %s", xerror.ErrCodeServiceInternalError, result.Code)
+	}
+
+	if result.AppName != "other-service" {
+		t.Errorf("Expected app name other-service, got %s", result.AppName)
+	}
+
+	// Without DebugInfo no local caller is captured
+	SetDebugInfo(false)
+	result = FromStatus(ToStatus(xErr), nil)
+	if result.Caller != "" || result.Detail != "" || result.Callers != nil || len(result.Frames()) != 0 {
+		t.Errorf("Expected no caller without DebugInfo, got %s %s", result.Caller, result.Detail)
+	}
+
+	result = FromStatus(status.New(codes.PermissionDenied, ""), nil)
+	if result.Code != xerror.ErrCodePermissionDenied {
+		t.Errorf("Expected code %s, got %s", xerror.ErrCodePermissionDenied, result.Code)
+	}
+}
+
+func TestStatusFieldViolations(t *testing.T) {
+	xErr := xerror.NewError(xerror.ErrCodeInvalidRequest,
+		xerror.WithFieldViolation("email", "email", "email is invalid", "x"),
+	)
+
+	st := ToStatus(xErr)
+
+	var badRequest *errdetails.BadRequest
+	for _, d := range st.Details() {
+		if v, ok := d.(*errdetails.BadRequest); ok {
+			badRequest = v
+		}
+	}
+
+	if badRequest == nil || len(badRequest.FieldViolations) != 1 {
+		t.Fatalf("Expected BadRequest detail with 1 violation, got %v", badRequest)
+	}
+
+	if badRequest.FieldViolations[0].Field != "email" || badRequest.FieldViolations[0].Reason != "email" {
+		t.Errorf("Expected email violation, got %v", badRequest.FieldViolations[0])
+	}
+
+	result := FromStatus(st, nil)
+	if len(result.Violations) != 1 {
+		t.Fatalf("Expected 1 violation, got %d", len(result.Violations))
+	}
+
+	v := result.Violations[0]
+	if v.Field != "email" || v.Rule != "email" || v.Message != "email is invalid" {
+		t.Errorf("Expected violation to round trip, got %+v", v)
+	}
+}
+
+func TestStatusFullDetails(t *testing.T) {
+	defer SetFullDetails(false)
+
+	xErr := xerror.NewError(xerror.ErrCodeNotFound,
+		xerror.WithMessage("user not found"),
+		xerror.WithCause(errors.New("record not found")),
+	)
+
+	// Off by default
+	if result := FromStatus(ToStatus(xErr), nil); result.Error() == xErr.Error() {
+		t.Error("Expected the remote cause chain to be lost without full details")
+	}
+
+	SetFullDetails(true)
+	result := FromStatus(ToStatus(xErr), nil)
+
+	if result.Code != xerror.ErrCodeNotFound || result.Message != "user not found" {
+		t.Errorf("Expected code and message, got %v", result)
+	}
+
+	if !time.Time(result.Timestamp).Equal(time.Time(xErr.Timestamp)) {
+		t.Errorf("Expected lossless timestamp, got %v", time.Time(result.Timestamp))
+	}
+
+	if len(result.Frames()) != len(xErr.Frames()) {
+		t.Errorf("Expected %d frames, got %d", len(xErr.Frames()), len(result.Frames()))
+	}
+
+	if result.Error() != xErr.Error() {
+		t.Errorf("Expected remote cause chain %q, got %q", xErr.Error(), result.Error())
+	}
+}
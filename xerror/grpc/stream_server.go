@@ -0,0 +1,123 @@
+package grpc
+
+import (
+	"context"
+	"errors"
+	"io"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"google.golang.org/grpc"
+)
+
+type StreamServerErrorTranslator func(err error) error
+type StreamServerOnFinished func(err error, stats StreamServerStats)
+
+// StreamServerStats is reported to StreamServerOnFinished when the stream is finished
+type StreamServerStats struct {
+	SentMessages     int64
+	ReceivedMessages int64
+	Duration         time.Duration
+}
+
+var _ grpc.ServerStream = &streamServerWrapper{}
+
+type StreamServerWrapper interface {
+	grpc.ServerStream
+	SetErrorTranslator(fn StreamServerErrorTranslator)
+	SetOnFinished(fn StreamServerOnFinished)
+	// SetContext replaces the context returned by Context, use it to inject loggers or trace ids derived from the stream context
+	SetContext(ctx context.Context)
+	// Finish translates the error returned by the handler and fires the on-finished callback once
+	Finish(err error) error
+}
+
+type streamServerWrapper struct {
+	grpc.ServerStream
+	ctx       context.Context
+	startTime time.Time
+	sent      atomic.Int64
+	received  atomic.Int64
+
+	// xerror translator
+	errorTranslator StreamServerErrorTranslator
+	finished        StreamServerOnFinished
+	finishOnce      sync.Once
+}
+
+func (sw *streamServerWrapper) SetErrorTranslator(fn StreamServerErrorTranslator) {
+	sw.errorTranslator = fn
+}
+func (sw *streamServerWrapper) SetOnFinished(fn StreamServerOnFinished) {
+	sw.finished = fn
+}
+func (sw *streamServerWrapper) SetContext(ctx context.Context) {
+	sw.ctx = ctx
+}
+
+func (sw *streamServerWrapper) Context() context.Context {
+	if sw.ctx != nil {
+		return sw.ctx
+	}
+	return sw.ServerStream.Context()
+}
+
+func (sw *streamServerWrapper) SendMsg(m any) error {
+	err := sw.ServerStream.SendMsg(m)
+	if err != nil {
+		err = sw.errorConvertor(err)
+		sw.callFinished(err)
+		return err
+	}
+	sw.sent.Add(1)
+	return nil
+}
+
+func (sw *streamServerWrapper) RecvMsg(m any) error {
+	// io.EOF only means the client closed its send side, the handler can still send messages
+	err := sw.ServerStream.RecvMsg(m)
+	if err != nil {
+		if !errors.Is(err, io.EOF) {
+			err = sw.errorConvertor(err)
+			sw.callFinished(err)
+		}
+		return err
+	}
+	sw.received.Add(1)
+	return nil
+}
+
+func (sw *streamServerWrapper) Finish(err error) error {
+	if err != nil {
+		err = sw.errorConvertor(err)
+	}
+	sw.callFinished(err)
+	return err
+}
+
+func (sw *streamServerWrapper) errorConvertor(err error) error {
+	if err != nil && sw.errorTranslator != nil {
+		return sw.errorTranslator(err)
+	}
+	return err
+}
+
+func (sw *streamServerWrapper) callFinished(err error) {
+	sw.finishOnce.Do(func() {
+		if sw.finished != nil {
+			sw.finished(err, StreamServerStats{
+				SentMessages:     sw.sent.Load(),
+				ReceivedMessages: sw.received.Load(),
+				Duration:         time.Since(sw.startTime),
+			})
+		}
+	})
+}
+
+func NewStreamServerWrapper(ss grpc.ServerStream) StreamServerWrapper {
+	return &streamServerWrapper{
+		ServerStream: ss,
+		startTime:    time.Now(),
+	}
+}
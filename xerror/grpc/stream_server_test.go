@@ -0,0 +1,159 @@
+package grpc
+
+import (
+	"context"
+	"errors"
+	"io"
+	"testing"
+)
+
+type streamCtxKey struct{}
+
+func TestNewStreamServerWrapper(t *testing.T) {
+	wrapper := NewStreamServerWrapper(&mockServerStream{})
+
+	if wrapper == nil {
+		t.Fatal("Expected non-nil wrapper")
+	}
+}
+
+func TestStreamServerWrapper_SetContext(t *testing.T) {
+	base := context.WithValue(context.Background(), streamCtxKey{}, "base")
+	wrapper := NewStreamServerWrapper(&mockServerStream{ctx: base})
+
+	// Without SetContext the stream context is returned
+	if wrapper.Context().Value(streamCtxKey{}) != "base" {
+		t.Error("Expected the underlying stream context")
+	}
+
+	derived := context.WithValue(base, streamCtxKey{}, "derived")
+	wrapper.SetContext(derived)
+
+	if wrapper.Context().Value(streamCtxKey{}) != "derived" {
+		t.Error("Expected the injected context")
+	}
+}
+
+func TestStreamServerWrapper_SendRecvMsg(t *testing.T) {
+	tests := []struct {
+		name              string
+		sendMsgErr        error
+		recvMsgErr        error
+		expectTranslation bool
+		expectCallback    bool
+	}{
+		{
+			name:              "no error",
+			expectTranslation: false,
+			expectCallback:    false,
+		},
+		{
+			name:              "EOF from client",
+			recvMsgErr:        io.EOF,
+			expectTranslation: false,
+			expectCallback:    false,
+		},
+		{
+			name:              "recv error",
+			recvMsgErr:        errors.New("recv error"),
+			expectTranslation: true,
+			expectCallback:    true,
+		},
+		{
+			name:              "send error",
+			sendMsgErr:        errors.New("send error"),
+			expectTranslation: true,
+			expectCallback:    true,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			wrapper := NewStreamServerWrapper(&mockServerStream{
+				sendMsgErr: tc.sendMsgErr,
+				recvMsgErr: tc.recvMsgErr,
+			})
+
+			var translatorCalled bool
+			var callbackCalled bool
+
+			wrapper.SetErrorTranslator(func(err error) error {
+				translatorCalled = true
+				return errors.New("translated error")
+			})
+
+			wrapper.SetOnFinished(func(err error, stats StreamServerStats) {
+				callbackCalled = true
+			})
+
+			recvErr := wrapper.RecvMsg(nil)
+			sendErr := wrapper.SendMsg(nil)
+
+			if tc.recvMsgErr == io.EOF && recvErr != io.EOF {
+				t.Errorf("Expected io.EOF to be returned as is, got %v", recvErr)
+			}
+
+			if tc.sendMsgErr != nil && sendErr == nil {
+				t.Error("Expected send error, got nil")
+			}
+
+			if tc.expectTranslation != translatorCalled {
+				t.Errorf("Expected translator called %v, got %v", tc.expectTranslation, translatorCalled)
+			}
+
+			if tc.expectCallback != callbackCalled {
+				t.Errorf("Expected onFinished called %v, got %v", tc.expectCallback, callbackCalled)
+			}
+		})
+	}
+}
+
+func TestStreamServerWrapper_Finish(t *testing.T) {
+	wrapper := NewStreamServerWrapper(&mockServerStream{})
+
+	var calls int
+	var finishedErr error
+	var finishedStats StreamServerStats
+
+	wrapper.SetErrorTranslator(func(err error) error {
+		return errors.New("translated error")
+	})
+
+	wrapper.SetOnFinished(func(err error, stats StreamServerStats) {
+		calls++
+		finishedErr = err
+		finishedStats = stats
+	})
+
+	_ = wrapper.RecvMsg(nil)
+	_ = wrapper.SendMsg(nil)
+	_ = wrapper.SendMsg(nil)
+
+	err := wrapper.Finish(errors.New("handler error"))
+
+	if err == nil || err.Error() != "translated error" {
+		t.Errorf("Expected translated error, got %v", err)
+	}
+
+	if finishedErr != err {
+		t.Errorf("Expected callback with translated error, got %v", finishedErr)
+	}
+
+	if finishedStats.SentMessages != 2 {
+		t.Errorf("Expected 2 sent messages, got %d", finishedStats.SentMessages)
+	}
+
+	if finishedStats.ReceivedMessages != 1 {
+		t.Errorf("Expected 1 received message, got %d", finishedStats.ReceivedMessages)
+	}
+
+	if finishedStats.Duration <= 0 {
+		t.Errorf("Expected positive duration, got %v", finishedStats.Duration)
+	}
+
+	// Callback is fired only once
+	_ = wrapper.Finish(nil)
+	if calls != 1 {
+		t.Errorf("Expected callback to be called once, got %d", calls)
+	}
+}
@@ -0,0 +1,164 @@
+package xerror
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"golang.org/x/text/language"
+	"gopkg.in/yaml.v3"
+	"io"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"sync"
+)
+
+type languageCtxKey struct{}
+
+var templateParamRegex = regexp.MustCompile(`\{(\w+)\}`)
+
+// Catalog holds message templates keyed by language and error code. Templates use {name} placeholders
+// which are replaced with the params, Info of the xerror when it is localized
+type Catalog struct {
+	mu       sync.RWMutex
+	fallback language.Tag
+	tags     []language.Tag
+	messages map[language.Tag]map[string]string
+	matcher  language.Matcher
+}
+
+// DefaultCatalog falls back to English
+var DefaultCatalog = NewCatalog(language.English)
+
+func NewCatalog(fallback language.Tag) *Catalog {
+	return &Catalog{
+		fallback: fallback,
+		tags:     []language.Tag{fallback},
+		messages: map[language.Tag]map[string]string{
+			fallback: make(map[string]string),
+		},
+		matcher: language.NewMatcher([]language.Tag{fallback}),
+	}
+}
+
+func (c *Catalog) Add(lang language.Tag, code, template string) *Catalog {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if _, ok := c.messages[lang]; !ok {
+		c.messages[lang] = make(map[string]string)
+		c.tags = append(c.tags, lang)
+		c.matcher = language.NewMatcher(c.tags)
+	}
+	c.messages[lang][code] = template
+	return c
+}
+
+// LoadJSON loads templates from {"<language>": {"<code>": "<template>"}}
+func (c *Catalog) LoadJSON(r io.Reader) error {
+	var m map[string]map[string]string
+	if err := json.NewDecoder(r).Decode(&m); err != nil {
+		return err
+	}
+	return c.load(m)
+}
+
+// LoadYAML is like LoadJSON with YAML document
+func (c *Catalog) LoadYAML(r io.Reader) error {
+	var m map[string]map[string]string
+	if err := yaml.NewDecoder(r).Decode(&m); err != nil {
+		return err
+	}
+	return c.load(m)
+}
+
+// LoadFile loads a .json, .yaml or .yml file
+func (c *Catalog) LoadFile(path string) error {
+	f, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".json":
+		return c.LoadJSON(f)
+	case ".yaml", ".yml":
+		return c.LoadYAML(f)
+	default:
+		return NewError(ErrCodeInvalidRequest, WithMessage(fmt.Sprintf("unsupported catalog file %s", path)))
+	}
+}
+
+func (c *Catalog) load(m map[string]map[string]string) error {
+	for lang, messages := range m {
+		tag, err := language.Parse(lang)
+		if err != nil {
+			return Wrap(err, ErrCodeInvalidRequest, WithMessage(fmt.Sprintf("invalid language %s", lang)))
+		}
+		for code, template := range messages {
+			c.Add(tag, code, template)
+		}
+	}
+	return nil
+}
+
+// Message resolves the template of code for the best matching language, the fallback language is used when
+// the matched language has no template for code
+func (c *Catalog) Message(code string, params map[string]any, langs ...language.Tag) (string, bool) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	_, i, _ := c.matcher.Match(langs...)
+	template, ok := c.messages[c.tags[i]][code]
+	if !ok {
+		template, ok = c.messages[c.fallback][code]
+	}
+	if !ok {
+		return "", false
+	}
+	return renderTemplate(template, params), true
+}
+
+// renderTemplate replaces {name} placeholders with params, unknown placeholders are kept
+func renderTemplate(template string, params map[string]any) string {
+	return templateParamRegex.ReplaceAllStringFunc(template, func(s string) string {
+		if v, ok := params[s[1:len(s)-1]]; ok {
+			return fmt.Sprintf("%v", v)
+		}
+		return s
+	})
+}
+
+// Localize returns a copy of err with the message of the language in ctx, err is returned as is when there is no template
+func (c *Catalog) Localize(ctx context.Context, err *Error) *Error {
+	if err == nil {
+		return nil
+	}
+	msg, ok := c.Message(err.Code, err.Info, LanguageFromContext(ctx)...)
+	if !ok {
+		return err
+	}
+	cp := *err
+	cp.Message = msg
+	return &cp
+}
+
+// WithLanguage sets the preferred languages used by Catalog.Localize
+func WithLanguage(ctx context.Context, langs ...language.Tag) context.Context {
+	return context.WithValue(ctx, languageCtxKey{}, langs)
+}
+
+// WithAcceptLanguage sets the preferred languages from an Accept-Language header
+func WithAcceptLanguage(ctx context.Context, acceptLanguage string) context.Context {
+	langs, _, err := language.ParseAcceptLanguage(acceptLanguage)
+	if err != nil || len(langs) == 0 {
+		return ctx
+	}
+	return WithLanguage(ctx, langs...)
+}
+
+func LanguageFromContext(ctx context.Context) []language.Tag {
+	if langs, ok := ctx.Value(languageCtxKey{}).([]language.Tag); ok {
+		return langs
+	}
+	return nil
+}
@@ -0,0 +1,164 @@
+package xerror
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"golang.org/x/text/language"
+)
+
+func TestCatalog_Message(t *testing.T) {
+	c := NewCatalog(language.English).
+		Add(language.English, ErrCodeNotFound, "{resource} not found").
+		Add(language.Thai, ErrCodeNotFound, "ไม่พบ{resource}").
+		Add(language.English, ErrCodeRateLimitExceeded, "Retry after {retry_after} seconds")
+
+	params := map[string]any{"resource": "user", "retry_after": 30}
+
+	tests := []struct {
+		name     string
+		code     string
+		langs    []language.Tag
+		expected string
+		found    bool
+	}{
+		{
+			name:     "exact language",
+			code:     ErrCodeNotFound,
+			langs:    []language.Tag{language.Thai},
+			expected: "ไม่พบuser",
+			found:    true,
+		},
+		{
+			name:     "regional variant",
+			code:     ErrCodeNotFound,
+			langs:    []language.Tag{language.MustParse("th-TH")},
+			expected: "ไม่พบuser",
+			found:    true,
+		},
+		{
+			name:     "no language uses fallback",
+			code:     ErrCodeNotFound,
+			expected: "user not found",
+			found:    true,
+		},
+		{
+			name:     "unknown language uses fallback",
+			code:     ErrCodeNotFound,
+			langs:    []language.Tag{language.Japanese},
+			expected: "user not found",
+			found:    true,
+		},
+		{
+			name:     "missing translation uses fallback language",
+			code:     ErrCodeRateLimitExceeded,
+			langs:    []language.Tag{language.Thai},
+			expected: "Retry after 30 seconds",
+			found:    true,
+		},
+		{
+			name:  "missing code",
+			code:  ErrCodeInternalError,
+			langs: []language.Tag{language.Thai},
+			found: false,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			msg, ok := c.Message(tc.code, params, tc.langs...)
+			if ok != tc.found {
+				t.Fatalf("Expected found %v, got %v", tc.found, ok)
+			}
+			if msg != tc.expected {
+				t.Errorf("Expected message %q, got %q", tc.expected, msg)
+			}
+		})
+	}
+
+	// Unknown params are kept
+	msg, _ := c.Message(ErrCodeNotFound, nil)
+	if msg != "{resource} not found" {
+		t.Errorf("Expected placeholder to be kept, got %q", msg)
+	}
+}
+
+func TestCatalog_LoadFile(t *testing.T) {
+	c := NewCatalog(language.English)
+
+	if err := c.LoadFile("testdata/messages.yaml"); err != nil {
+		t.Fatalf("Failed to load yaml: %v", err)
+	}
+
+	if err := c.LoadFile("testdata/messages.json"); err != nil {
+		t.Fatalf("Failed to load json: %v", err)
+	}
+
+	msg, ok := c.Message(ErrCodePermissionDenied, map[string]any{"action": "ลบข้อมูล"}, language.Thai)
+	if !ok || msg != "คุณไม่มีสิทธิ์ลบข้อมูล" {
+		t.Errorf("Expected Thai message from json, got %q", msg)
+	}
+
+	msg, ok = c.Message(ErrCodeNotFound, map[string]any{"resource": "order"}, language.English)
+	if !ok || msg != "order not found" {
+		t.Errorf("Expected English message from yaml, got %q", msg)
+	}
+
+	// Missing file
+	if err := c.LoadFile("testdata/messages.txt"); err == nil {
+		t.Error("Expected error for missing file")
+	}
+
+	// Unsupported extension
+	if err := c.LoadFile("i18n.go"); !IsErrorCode(err, ErrCodeInvalidRequest) {
+		t.Errorf("Expected %s for unsupported file, got %v", ErrCodeInvalidRequest, err)
+	}
+
+	// Invalid language
+	if err := c.LoadJSON(strings.NewReader(`{"not a language!": {"NOT_FOUND": "x"}}`)); !IsErrorCode(err, ErrCodeInvalidRequest) {
+		t.Errorf("Expected %s for invalid language, got %v", ErrCodeInvalidRequest, err)
+	}
+}
+
+func TestCatalog_Localize(t *testing.T) {
+	c := NewCatalog(language.English).
+		Add(language.English, ErrCodeNotFound, "{resource} not found").
+		Add(language.Thai, ErrCodeNotFound, "ไม่พบ{resource}")
+
+	err := NewError(ErrCodeNotFound, WithJSONInfo("resource", "user"))
+
+	// Language from Accept-Language header
+	ctx := WithAcceptLanguage(context.Background(), "th-TH,th;q=0.9,en;q=0.8")
+	localized := c.Localize(ctx, err)
+
+	if localized.Message != "ไม่พบuser" {
+		t.Errorf("Expected Thai message, got %q", localized.Message)
+	}
+
+	if err.Message == localized.Message {
+		t.Error("Expected the original error to be untouched")
+	}
+
+	// Language set explicitly
+	ctx = WithLanguage(context.Background(), language.English)
+	if msg := c.Localize(ctx, err).Message; msg != "user not found" {
+		t.Errorf("Expected English message, got %q", msg)
+	}
+
+	// Invalid header keeps the context
+	ctx = WithAcceptLanguage(context.Background(), "!!!")
+	if LanguageFromContext(ctx) != nil {
+		t.Error("Expected no language for invalid header")
+	}
+
+	// No template keeps the error
+	other := NewError(ErrCodeInternalError)
+	if c.Localize(ctx, other) != other {
+		t.Error("Expected error without template to be returned as is")
+	}
+
+	if c.Localize(ctx, nil) != nil {
+		t.Error("Expected nil for nil error")
+	}
+}
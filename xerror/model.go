@@ -1,6 +1,7 @@
 package xerror
 
 import (
+	"encoding/json"
 	"fmt"
 	"github.com/pkg/errors"
 	"strconv"
@@ -32,19 +33,170 @@ type Error struct {
 	Code    string `json:"code"`
 	Message string `json:"message"`
 	// Detail contains a more human-friendly message which may include long instructions to fix the error
-	Caller    string         `json:"caller,omitempty"`
-	Detail    string         `json:"detail,omitempty"`
-	AppName   string         `json:"app_name,omitempty"`
-	Info      map[string]any `json:"info,omitempty"`
-	Timestamp Timestamp      `json:"timestamp"`
-	Callers   *stack         `json:"callers,omitempty"`
+	Caller  string         `json:"caller,omitempty"`
+	Detail  string         `json:"detail,omitempty"`
+	AppName string         `json:"app_name,omitempty"`
+	Info    map[string]any `json:"info,omitempty"`
+	// Violations lists the fields which failed validation
+	Violations []FieldViolation `json:"violations,omitempty"`
+	// Errors are the aggregated errors of a MultiError, see MultiError.ToError
+	Errors []*Error `json:"errors,omitempty"`
+	// Provenance lists the services the xerror went through, see Receive
+	Provenance []Hop     `json:"provenance,omitempty"`
+	Timestamp  Timestamp `json:"timestamp"`
+	Callers    *stack    `json:"callers,omitempty"`
+	// Cause is the underlying error that triggered this one, it is serialized as "cause" in JSON
+	Cause error `json:"-"`
+	// frames is the stack decoded from JSON
+	frames []Frame
+	// def is the definition the xerror was created from, see Define
+	def *Definition
 }
 
 // Error implements xerror.
 func (err *Error) Error() string {
+	if err.Cause != nil {
+		return fmt.Sprintf("%s: %s: %s", err.Code, err.Message, err.Cause.Error())
+	}
 	return fmt.Sprintf("%s: %s", err.Code, err.Message)
 }
 
+// Unwrap returns the cause so errors.Is and errors.As can walk through an xerror.
+func (err *Error) Unwrap() error {
+	return err.Cause
+}
+
+// Is reports whether target is an *Error with the same code or the *Definition err was created from.
+func (err *Error) Is(target error) bool {
+	switch t := target.(type) {
+	case *Error:
+		return t != nil && t.Code != "" && t.Code == err.Code
+	case *Definition:
+		return t != nil && t == err.def
+	}
+	return false
+}
+
+type errorJSON Error
+
+func (err *Error) MarshalJSON() ([]byte, error) {
+	return json.Marshal(&struct {
+		*errorJSON
+		Callers []Frame `json:"callers,omitempty"`
+		Cause   any     `json:"cause,omitempty"`
+	}{
+		errorJSON: (*errorJSON)(err),
+		Callers:   err.Frames(),
+		Cause:     newCauseJSON(err.Cause),
+	})
+}
+
+// UnmarshalJSON decodes the resolved frames of callers, the program counters written by older versions
+// are accepted and dropped since they can only be resolved in the process which captured them
+func (err *Error) UnmarshalJSON(b []byte) error {
+	v := struct {
+		*errorJSON
+		Callers json.RawMessage `json:"callers,omitempty"`
+		Cause   json.RawMessage `json:"cause,omitempty"`
+	}{
+		errorJSON: (*errorJSON)(err),
+	}
+	if e := json.Unmarshal(b, &v); e != nil {
+		return e
+	}
+	frames, e := parseCallersJSON(v.Callers)
+	if e != nil {
+		return e
+	}
+	err.frames = frames
+	cause, e := parseCauseJSON(v.Cause)
+	if e != nil {
+		return e
+	}
+	err.Cause = cause
+	return nil
+}
+
+func parseCallersJSON(b json.RawMessage) ([]Frame, error) {
+	if len(b) == 0 || string(b) == "null" {
+		return nil, nil
+	}
+	var frames []Frame
+	err := json.Unmarshal(b, &frames)
+	if err == nil {
+		return frames, nil
+	}
+	var pcs []uint64
+	if json.Unmarshal(b, &pcs) == nil {
+		return nil, nil
+	}
+	return nil, err
+}
+
+// causeJSON is the serialized form of a cause which isn't an *Error.
+type causeJSON struct {
+	Message string `json:"message"`
+	Cause   any    `json:"cause,omitempty"`
+}
+
+func newCauseJSON(err error) any {
+	if err == nil {
+		return nil
+	}
+	if xErr, ok := err.(*Error); ok {
+		return xErr
+	}
+	return &causeJSON{
+		Message: err.Error(),
+		Cause:   newCauseJSON(errors.Unwrap(err)),
+	}
+}
+
+func parseCauseJSON(b json.RawMessage) (error, error) {
+	if len(b) == 0 || string(b) == "null" {
+		return nil, nil
+	}
+	var probe struct {
+		Code *string `json:"code"`
+	}
+	if err := json.Unmarshal(b, &probe); err != nil {
+		return nil, err
+	}
+	if probe.Code != nil {
+		xErr := &Error{}
+		if err := json.Unmarshal(b, xErr); err != nil {
+			return nil, err
+		}
+		return xErr, nil
+	}
+	var v struct {
+		Message string          `json:"message"`
+		Cause   json.RawMessage `json:"cause,omitempty"`
+	}
+	if err := json.Unmarshal(b, &v); err != nil {
+		return nil, err
+	}
+	cause, err := parseCauseJSON(v.Cause)
+	if err != nil {
+		return nil, err
+	}
+	return &remoteCause{message: v.Message, cause: cause}, nil
+}
+
+// remoteCause is a plain error restored from JSON, it keeps the message and the rest of the chain.
+type remoteCause struct {
+	message string
+	cause   error
+}
+
+func (e *remoteCause) Error() string {
+	return e.message
+}
+
+func (e *remoteCause) Unwrap() error {
+	return e.cause
+}
+
 func (err *Error) StackTrace() errors.StackTrace {
 	if err.Callers == nil {
 		return make([]errors.Frame, 0)
@@ -73,7 +225,7 @@ func (t *Timestamp) UnmarshalJSON(b []byte) error {
 	return nil
 }
 
-// TimestampNow is a shortcut for Timestamp(timeutils.Now()).
+// TimestampNow is a shortcut for Timestamp(now()), the clock can be replaced with SetClock.
 func TimestampNow() Timestamp {
-	return Timestamp(time.Now().UTC())
+	return Timestamp(now().UTC())
 }
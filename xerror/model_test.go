@@ -2,6 +2,9 @@ package xerror
 
 import (
 	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
 	"testing"
 	"time"
 )
@@ -159,3 +162,112 @@ func TestErrorConstants(t *testing.T) {
 		}
 	}
 }
+
+func TestError_ErrorWithCause(t *testing.T) {
+	err := &Error{
+		Code:    "TEST_CODE",
+		Message: "Test message",
+		Cause:   errors.New("root cause"),
+	}
+
+	expected := "TEST_CODE: Test message: root cause"
+	if err.Error() != expected {
+		t.Errorf("Expected error string %q, got %q", expected, err.Error())
+	}
+}
+
+func TestError_Is(t *testing.T) {
+	tests := []struct {
+		name     string
+		err      *Error
+		target   error
+		expected bool
+	}{
+		{
+			name:     "same code",
+			err:      &Error{Code: ErrCodeNotFound},
+			target:   &Error{Code: ErrCodeNotFound},
+			expected: true,
+		},
+		{
+			name:     "different code",
+			err:      &Error{Code: ErrCodeNotFound},
+			target:   &Error{Code: ErrCodeInternalError},
+			expected: false,
+		},
+		{
+			name:     "empty code",
+			err:      &Error{},
+			target:   &Error{},
+			expected: false,
+		},
+		{
+			name:     "non-xerror target",
+			err:      &Error{Code: ErrCodeNotFound},
+			target:   errors.New(ErrCodeNotFound),
+			expected: false,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			if result := tc.err.Is(tc.target); result != tc.expected {
+				t.Errorf("Expected Is to return %v, got %v", tc.expected, result)
+			}
+		})
+	}
+}
+
+func TestError_MarshalJSONWithCause(t *testing.T) {
+	root := fmt.Errorf("query failed: %w", errors.New("connection refused"))
+	inner := &Error{
+		Code:      ErrCodeServiceInternalError,
+		Message:   "inner",
+		Timestamp: TimestampNow(),
+		Cause:     root,
+	}
+	err := &Error{
+		Code:      ErrCodeInternalError,
+		Message:   "outer",
+		Timestamp: TimestampNow(),
+		Cause:     inner,
+	}
+
+	b, mErr := json.Marshal(err)
+	if mErr != nil {
+		t.Fatalf("Failed to marshal error: %v", mErr)
+	}
+
+	var decoded Error
+	if uErr := json.Unmarshal(b, &decoded); uErr != nil {
+		t.Fatalf("Failed to unmarshal error: %v", uErr)
+	}
+
+	if decoded.Code != ErrCodeInternalError || decoded.Message != "outer" {
+		t.Errorf("Expected outer error, got %s: %s", decoded.Code, decoded.Message)
+	}
+
+	var decodedInner *Error
+	if !errors.As(decoded.Cause, &decodedInner) {
+		t.Fatalf("Expected *Error cause, got %T", decoded.Cause)
+	}
+
+	if decodedInner.Code != ErrCodeServiceInternalError || decodedInner.Message != "inner" {
+		t.Errorf("Expected inner error, got %s: %s", decodedInner.Code, decodedInner.Message)
+	}
+
+	if decodedInner.Cause == nil || decodedInner.Cause.Error() != "query failed: connection refused" {
+		t.Errorf("Expected plain cause to be restored, got %v", decodedInner.Cause)
+	}
+
+	last := errors.Unwrap(decodedInner.Cause)
+	if last == nil || last.Error() != "connection refused" {
+		t.Errorf("Expected root cause to be restored, got %v", last)
+	}
+
+	// Errors without cause don't emit the cause key
+	b, _ = json.Marshal(&Error{Code: ErrCodeNotFound})
+	if strings.Contains(string(b), "cause") {
+		t.Errorf("Expected no cause key, got %s", string(b))
+	}
+}
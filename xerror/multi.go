@@ -0,0 +1,139 @@
+package xerror
+
+import (
+	"encoding/json"
+	"github.com/pkg/errors"
+	"strings"
+)
+
+// MultiError aggregates several errors with errors.Join semantics, errors.Is and errors.As match any of its errors
+type MultiError struct {
+	errs []error
+}
+
+// Join returns a MultiError holding the non-nil errs, nested MultiError are flattened. Join returns nil if every err is nil
+func Join(errs ...error) error {
+	m := &MultiError{}
+	m.Append(errs...)
+	return m.ErrorOrNil()
+}
+
+// Append adds the non-nil errs
+func (m *MultiError) Append(errs ...error) *MultiError {
+	for _, err := range errs {
+		if err == nil {
+			continue
+		}
+		if child, ok := err.(*MultiError); ok {
+			m.errs = append(m.errs, child.errs...)
+			continue
+		}
+		m.errs = append(m.errs, err)
+	}
+	return m
+}
+
+// ErrorOrNil returns nil when no error has been appended
+func (m *MultiError) ErrorOrNil() error {
+	if m == nil || len(m.errs) == 0 {
+		return nil
+	}
+	return m
+}
+
+func (m *MultiError) Errors() []error {
+	return m.errs
+}
+
+func (m *MultiError) Unwrap() []error {
+	return m.errs
+}
+
+// Error implements xerror, messages are separated by newline like errors.Join
+func (m *MultiError) Error() string {
+	msgs := make([]string, 0, len(m.errs))
+	for _, err := range m.errs {
+		msgs = append(msgs, err.Error())
+	}
+	return strings.Join(msgs, "\n")
+}
+
+// Dominant returns the error with the highest http status of its code, the first one wins on tie.
+// Non xerror is treated as ErrCodeInternalError
+func (m *MultiError) Dominant() error {
+	var dominant error
+	status := 0
+	for _, err := range m.errs {
+		if s := HTTPStatus(GetErrorCode(err)); s > status {
+			dominant = err
+			status = s
+		}
+	}
+	return dominant
+}
+
+// Code returns the code of the dominant error
+func (m *MultiError) Code() string {
+	return GetErrorCode(m.Dominant())
+}
+
+// ToError converts the aggregated errors to one xerror with the dominant code and the joined messages, Errors holds
+// every error, non xerror ones are wrapped as ErrCodeInternalError
+func (m *MultiError) ToError() *Error {
+	errs := make([]*Error, 0, len(m.errs))
+	for _, e := range m.errs {
+		xErr := AsError(e)
+		if xErr == nil {
+			xErr = Wrap(e, ErrCodeInternalError)
+		}
+		errs = append(errs, xErr)
+	}
+	err := newError(m.Code(), WithMessage(GetErrorMessage(m)), WithCause(m))
+	err.Errors = errs
+	return err
+}
+
+// AsError returns the first xerror in the chain of err, aggregated errors found first are converted with
+// MultiError.ToError so their dominant code is kept. nil is returned when the chain has no xerror
+func AsError(err error) *Error {
+	apiErr, multiErr := lookupError(err)
+	if apiErr != nil {
+		return apiErr
+	}
+	if multiErr != nil {
+		return multiErr.ToError()
+	}
+	return nil
+}
+
+func (m *MultiError) MarshalJSON() ([]byte, error) {
+	errs := make([]any, 0, len(m.errs))
+	for _, err := range m.errs {
+		errs = append(errs, newCauseJSON(err))
+	}
+	return json.Marshal(&struct {
+		Code    string `json:"code"`
+		Message string `json:"message"`
+		Errors  []any  `json:"errors"`
+	}{
+		Code:    m.Code(),
+		Message: GetErrorMessage(m),
+		Errors:  errs,
+	})
+}
+
+// lookupError walks the chain of err and returns the first *Error or aggregated errors found
+func lookupError(err error) (*Error, *MultiError) {
+	for err != nil {
+		switch e := err.(type) {
+		case *Error:
+			return e, nil
+		case *MultiError:
+			return nil, e
+		case interface{ Unwrap() []error }:
+			return nil, &MultiError{errs: e.Unwrap()}
+		}
+		err = errors.Unwrap(err)
+	}
+	return nil, nil
+}
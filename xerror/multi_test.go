@@ -0,0 +1,210 @@
+package xerror
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+)
+
+func TestJoin(t *testing.T) {
+	// Test with nil errors only
+	if err := Join(nil, nil); err != nil {
+		t.Errorf("Expected nil, got %v", err)
+	}
+
+	notFound := NewError(ErrCodeNotFound, WithMessage("user not found"))
+	denied := NewError(ErrCodePermissionDenied, WithMessage("access denied"))
+	plain := errors.New("plain error")
+
+	err := Join(notFound, nil, Join(denied, plain))
+
+	var multiErr *MultiError
+	if !errors.As(err, &multiErr) {
+		t.Fatalf("Expected *MultiError, got %T", err)
+	}
+
+	// Nested MultiError is flattened and nil is skipped
+	if len(multiErr.Errors()) != 3 {
+		t.Fatalf("Expected 3 errors, got %d", len(multiErr.Errors()))
+	}
+
+	if !errors.Is(err, plain) {
+		t.Error("Expected errors.Is to match any child")
+	}
+
+	var xErr *Error
+	if !errors.As(err, &xErr) || xErr != notFound {
+		t.Error("Expected errors.As to find the first xerror")
+	}
+
+	expected := notFound.Error() + "\n" + denied.Error() + "\n" + plain.Error()
+	if err.Error() != expected {
+		t.Errorf("Expected error string %q, got %q", expected, err.Error())
+	}
+}
+
+func TestMultiError_Code(t *testing.T) {
+	tests := []struct {
+		name     string
+		errs     []error
+		expected string
+	}{
+		{
+			name:     "highest http status wins",
+			errs:     []error{NewError(ErrCodeNotFound), NewError(ErrCodeRateLimitExceeded), NewError(ErrCodeInvalidRequest)},
+			expected: ErrCodeRateLimitExceeded,
+		},
+		{
+			name:     "first wins on tie",
+			errs:     []error{NewError(ErrCodeInvalidEnum), NewError(ErrCodeInvalidRequest)},
+			expected: ErrCodeInvalidEnum,
+		},
+		{
+			name:     "non xerror is internal",
+			errs:     []error{NewError(ErrCodeNotFound), errors.New("plain error")},
+			expected: ErrCodeInternalError,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			m := (&MultiError{}).Append(tc.errs...)
+			if code := m.Code(); code != tc.expected {
+				t.Errorf("Expected dominant code %s, got %s", tc.expected, code)
+			}
+			if code := GetErrorCode(m); code != tc.expected {
+				t.Errorf("Expected GetErrorCode %s, got %s", tc.expected, code)
+			}
+		})
+	}
+}
+
+func TestMultiError_ErrorOrNil(t *testing.T) {
+	var m *MultiError
+	if m.ErrorOrNil() != nil {
+		t.Error("Expected nil for nil MultiError")
+	}
+
+	m = &MultiError{}
+	if m.ErrorOrNil() != nil {
+		t.Error("Expected nil for empty MultiError")
+	}
+
+	m.Append(errors.New("plain error"))
+	if m.ErrorOrNil() == nil {
+		t.Error("Expected non-nil error")
+	}
+}
+
+func TestMultiError_IsErrorCodeAndMessage(t *testing.T) {
+	err := fmt.Errorf("batch: %w", Join(
+		NewError(ErrCodeNotFound, WithMessage("user not found")),
+		NewError(ErrCodeInvalidRequest, WithMessage("email is invalid"), WithFieldViolation("email", "email", "", nil)),
+	))
+
+	if !IsErrorCode(err, ErrCodeNotFound) || !IsErrorCode(err, ErrCodeInvalidRequest) {
+		t.Error("Expected IsErrorCode to match any child")
+	}
+
+	if IsErrorCode(err, ErrCodeInternalError) {
+		t.Error("Expected IsErrorCode not to match missing code")
+	}
+
+	if msg := GetErrorMessage(err); msg != "user not found; email is invalid" {
+		t.Errorf("Expected joined message, got %q", msg)
+	}
+
+	if v := GetFieldViolations(err); len(v) != 1 || v[0].Field != "email" {
+		t.Errorf("Expected violations of children, got %+v", v)
+	}
+
+	// errors.Join is understood as well
+	joined := errors.Join(NewError(ErrCodeAlreadyExists), errors.New("plain error"))
+	if !IsErrorCode(joined, ErrCodeAlreadyExists) || !IsErrorCode(joined, ErrCodeInternalError) {
+		t.Error("Expected IsErrorCode to match children of errors.Join")
+	}
+}
+
+func TestMultiError_MarshalJSON(t *testing.T) {
+	err := Join(
+		NewError(ErrCodeNotFound, WithMessage("user not found")),
+		errors.New("plain error"),
+	)
+
+	b, mErr := json.Marshal(err)
+	if mErr != nil {
+		t.Fatalf("Failed to marshal error: %v", mErr)
+	}
+
+	var decoded struct {
+		Code    string            `json:"code"`
+		Message string            `json:"message"`
+		Errors  []json.RawMessage `json:"errors"`
+	}
+	if uErr := json.Unmarshal(b, &decoded); uErr != nil {
+		t.Fatalf("Failed to unmarshal error: %v", uErr)
+	}
+
+	if decoded.Code != ErrCodeInternalError {
+		t.Errorf("Expected dominant code %s, got %s", ErrCodeInternalError, decoded.Code)
+	}
+
+	if decoded.Message != "user not found" {
+		t.Errorf("Expected message %q, got %q", "user not found", decoded.Message)
+	}
+
+	if len(decoded.Errors) != 2 {
+		t.Fatalf("Expected 2 children, got %d", len(decoded.Errors))
+	}
+
+	var child Error
+	if uErr := json.Unmarshal(decoded.Errors[0], &child); uErr != nil || child.Code != ErrCodeNotFound {
+		t.Errorf("Expected first child to be the xerror, got %s", string(decoded.Errors[0]))
+	}
+}
+
+func TestMultiError_ToError(t *testing.T) {
+	notFound := NewError(ErrCodeNotFound, WithMessage("user not found"))
+	internal := NewError(ErrCodeInternalError, WithMessage("db down"))
+	plain := errors.New("sql: connection refused")
+
+	err := AsError(Wrap(Join(notFound, internal, plain), ErrCodeInvalidRequest))
+	if err.Code != ErrCodeInvalidRequest {
+		t.Errorf("Expected the outer xerror to win, got %s", err.Code)
+	}
+
+	err = AsError(fmt.Errorf("batch: %w", Join(notFound, internal, plain)))
+	if err.Code != ErrCodeInternalError {
+		t.Errorf("Expected dominant code %s, got %s", ErrCodeInternalError, err.Code)
+	}
+	if len(err.Errors) != 3 || err.Errors[0] != notFound || err.Errors[1] != internal {
+		t.Fatalf("Expected every error, got %v", err.Errors)
+	}
+	if err.Errors[2].Code != ErrCodeInternalError || !errors.Is(err.Errors[2], plain) {
+		t.Errorf("Expected plain error wrapped as internal error, got %v", err.Errors[2])
+	}
+	if !errors.Is(err, notFound) {
+		t.Error("Expected errors.Is to match the aggregated errors")
+	}
+
+	b, mErr := MarshalPublic(err)
+	if mErr != nil {
+		t.Fatalf("Failed to marshal error: %v", mErr)
+	}
+	var m map[string]any
+	if uErr := json.Unmarshal(b, &m); uErr != nil {
+		t.Fatalf("Failed to unmarshal error: %v", uErr)
+	}
+	if errs, _ := m["errors"].([]any); len(errs) != 3 {
+		t.Errorf("Expected 3 errors in the body, got %v", m["errors"])
+	}
+	if strings.Contains(string(b), "connection refused") || strings.Contains(string(b), "caller") {
+		t.Errorf("Expected aggregated errors to be filtered by the profile, got %s", b)
+	}
+
+	if AsError(plain) != nil {
+		t.Error("Expected nil without xerror")
+	}
+}
@@ -41,3 +41,32 @@ func WithJSONInfo(key string, value any) ErrorOptionFunc {
 		return err
 	}
 }
+
+// WithCause attaches the underlying error, it is reachable through errors.Unwrap, errors.Is and errors.As
+func WithCause(cause error) ErrorOptionFunc {
+	return func(err *Error) *Error {
+		err.Cause = cause
+		return err
+	}
+}
+
+func WithFieldViolation(field, rule, message string, value any) ErrorOptionFunc {
+	return WithFieldViolations(FieldViolation{
+		Field:   field,
+		Rule:    rule,
+		Message: message,
+		Value:   value,
+	})
+}
+
+func WithFieldViolations(v ...FieldViolation) ErrorOptionFunc {
+	return func(err *Error) *Error {
+		err.Violations = append(err.Violations, v...)
+		return err
+	}
+}
+
+// WithRetryAfter stores the delay before the request can be retried in Info, it is kept in seconds so it survives JSON and gRPC
+func WithRetryAfter(d time.Duration) ErrorOptionFunc {
+	return WithJSONInfo(RetryAfterInfoKey, d.Seconds())
+}
@@ -1,6 +1,7 @@
 package xerror
 
 import (
+	"errors"
 	"testing"
 	"time"
 )
@@ -140,4 +141,49 @@ func TestWithJSONInfo(t *testing.T) {
 	if resultErr2.Info[key2] != value2 {
 		t.Errorf("Expected Info[%q] to be %d, got %v", key2, value2, resultErr2.Info[key2])
 	}
-}
\ No newline at end of file
+}
+func TestWithCause(t *testing.T) {
+	// Create a base error
+	baseErr := &Error{
+		Code: "TEST_CODE",
+	}
+
+	// Apply WithCause option
+	cause := errors.New("cause")
+	resultErr := WithCause(cause)(baseErr)
+
+	// Check that the cause was set correctly
+	if resultErr.Cause != cause {
+		t.Errorf("Expected cause %v, got %v", cause, resultErr.Cause)
+	}
+
+	if !errors.Is(resultErr, cause) {
+		t.Error("Expected errors.Is to match the cause")
+	}
+
+	// Check that the function returns the same error instance
+	if resultErr != baseErr {
+		t.Error("Expected WithCause to return the same error instance")
+	}
+}
+
+func TestWithFieldViolation(t *testing.T) {
+	baseErr := &Error{
+		Code: ErrCodeInvalidRequest,
+	}
+
+	resultErr := WithFieldViolation("age", "gte", "age must be at least 18", 17)(baseErr)
+	resultErr = WithFieldViolations(FieldViolation{Field: "name", Rule: "required"})(resultErr)
+
+	if len(resultErr.Violations) != 2 {
+		t.Fatalf("Expected 2 violations, got %d", len(resultErr.Violations))
+	}
+
+	if resultErr.Violations[0].Value != 17 {
+		t.Errorf("Expected rejected value 17, got %v", resultErr.Violations[0].Value)
+	}
+
+	if resultErr != baseErr {
+		t.Error("Expected WithFieldViolation to return the same error instance")
+	}
+}
@@ -0,0 +1,57 @@
+package otel
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/kurzgesagtz/xgo/xerror"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	semconv "go.opentelemetry.io/otel/semconv/v1.30.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// Attribute keys set by RecordError, CodeKey is set on the span itself so dashboards can group by code
+const (
+	CodeKey    = attribute.Key("xerror.code")
+	MessageKey = attribute.Key("xerror.message")
+	AppNameKey = attribute.Key("xerror.app_name")
+)
+
+// RecordError records err on the span of ctx, see RecordSpanError
+func RecordError(ctx context.Context, err error) {
+	RecordSpanError(trace.SpanFromContext(ctx), err)
+}
+
+// RecordSpanError records err as an exception event carrying the code, message, app name and stack of the xerror.
+// The span status is set to Error only when the http status of the code is 5xx, client errors don't fail the span
+func RecordSpanError(span trace.Span, err error) {
+	if err == nil || !span.IsRecording() {
+		return
+	}
+	code := xerror.GetErrorCode(err)
+	msg := xerror.GetErrorMessage(err)
+	if msg == "" {
+		msg = err.Error()
+	}
+	attrs := []attribute.KeyValue{
+		CodeKey.String(code),
+		MessageKey.String(msg),
+	}
+	var xErr *xerror.Error
+	if errors.As(err, &xErr) {
+		if xErr.AppName != "" {
+			attrs = append(attrs, AppNameKey.String(xErr.AppName))
+		}
+		if len(xErr.Frames()) > 0 {
+			attrs = append(attrs, semconv.ExceptionStacktraceKey.String(fmt.Sprintf("%+v", xErr)))
+		}
+	}
+	span.RecordError(err, trace.WithAttributes(attrs...))
+	span.SetAttributes(CodeKey.String(code))
+	if xerror.HTTPStatus(code) >= 500 {
+		span.SetStatus(codes.Error, msg)
+	}
+}
@@ -0,0 +1,139 @@
+package otel
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/kurzgesagtz/xgo/xerror"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+	"go.opentelemetry.io/otel/trace/noop"
+)
+
+// recordingSpan keeps what is recorded on it
+type recordingSpan struct {
+	noop.Span
+	err         error
+	eventAttrs  map[attribute.Key]attribute.Value
+	attrs       map[attribute.Key]attribute.Value
+	statusCode  codes.Code
+	description string
+}
+
+func newRecordingSpan() *recordingSpan {
+	return &recordingSpan{
+		eventAttrs: make(map[attribute.Key]attribute.Value),
+		attrs:      make(map[attribute.Key]attribute.Value),
+	}
+}
+
+func (s *recordingSpan) IsRecording() bool {
+	return true
+}
+
+func (s *recordingSpan) RecordError(err error, opts ...trace.EventOption) {
+	s.err = err
+	cfg := trace.NewEventConfig(opts...)
+	for _, kv := range cfg.Attributes() {
+		s.eventAttrs[kv.Key] = kv.Value
+	}
+}
+
+func (s *recordingSpan) SetAttributes(kv ...attribute.KeyValue) {
+	for _, a := range kv {
+		s.attrs[a.Key] = a.Value
+	}
+}
+
+func (s *recordingSpan) SetStatus(code codes.Code, description string) {
+	s.statusCode = code
+	s.description = description
+}
+
+func TestRecordSpanError(t *testing.T) {
+	err := xerror.NewError(xerror.ErrCodeServiceInternalError, xerror.WithMessage("database is down"))
+	err.AppName = "user-service"
+
+	span := newRecordingSpan()
+	RecordSpanError(span, err)
+
+	if span.err != err {
+		t.Errorf("Expected error to be recorded, got %v", span.err)
+	}
+
+	if v := span.eventAttrs[CodeKey].AsString(); v != xerror.ErrCodeServiceInternalError {
+		t.Errorf("Expected event code %s, got %s", xerror.ErrCodeServiceInternalError, v)
+	}
+
+	if v := span.eventAttrs[MessageKey].AsString(); v != "database is down" {
+		t.Errorf("Expected event message, got %s", v)
+	}
+
+	if v := span.eventAttrs[AppNameKey].AsString(); v != "user-service" {
+		t.Errorf("Expected event app name, got %s", v)
+	}
+
+	if v := span.eventAttrs["exception.stacktrace"].AsString(); !strings.Contains(v, "span_test.go") {
+		t.Errorf("Expected stack trace of the error, got %s", v)
+	}
+
+	if v := span.attrs[CodeKey].AsString(); v != xerror.ErrCodeServiceInternalError {
+		t.Errorf("Expected span code attribute, got %s", v)
+	}
+
+	if span.statusCode != codes.Error || span.description != "database is down" {
+		t.Errorf("Expected error status, got %v %s", span.statusCode, span.description)
+	}
+}
+
+func TestRecordSpanError_ClientError(t *testing.T) {
+	span := newRecordingSpan()
+	RecordSpanError(span, xerror.NewError(xerror.ErrCodeNotFound))
+
+	if span.statusCode != codes.Unset {
+		t.Errorf("Expected status to be unset for client errors, got %v", span.statusCode)
+	}
+
+	if v := span.attrs[CodeKey].AsString(); v != xerror.ErrCodeNotFound {
+		t.Errorf("Expected span code attribute, got %s", v)
+	}
+}
+
+func TestRecordSpanError_StandardError(t *testing.T) {
+	span := newRecordingSpan()
+	RecordSpanError(span, errors.New("boom"))
+
+	if v := span.eventAttrs[CodeKey].AsString(); v != xerror.ErrCodeInternalError {
+		t.Errorf("Expected code %s, got %s", xerror.ErrCodeInternalError, v)
+	}
+
+	if v := span.eventAttrs[MessageKey].AsString(); v != "boom" {
+		t.Errorf("Expected message boom, got %s", v)
+	}
+
+	if _, ok := span.eventAttrs[AppNameKey]; ok {
+		t.Error("Expected no app name")
+	}
+}
+
+func TestRecordError(t *testing.T) {
+	span := newRecordingSpan()
+	ctx := trace.ContextWithSpan(context.Background(), span)
+
+	RecordError(ctx, xerror.NewError(xerror.ErrCodeInternalError))
+	if span.err == nil {
+		t.Error("Expected error to be recorded on the span of the context")
+	}
+
+	// nil error and context without span are ignored
+	span = newRecordingSpan()
+	RecordError(trace.ContextWithSpan(context.Background(), span), nil)
+	if span.err != nil {
+		t.Error("Expected nil error to be ignored")
+	}
+	RecordError(context.Background(), errors.New("boom"))
+}
@@ -0,0 +1,35 @@
+package xerror
+
+import (
+	"fmt"
+	"runtime"
+)
+
+// NewPanicError converts a value recovered from a panic to ErrCodeServerPanic. The message is the registry default, the
+// value is kept in Info as "panic", which PublicProfile redacts, and as the cause when it is an error.
+// Call it from the deferred function which recovered r so Callers and Caller start at the statement which panicked
+func NewPanicError(r any) *Error {
+	err := newError(ErrCodeServerPanic, WithJSONInfo("panic", fmt.Sprintf("%v", r)))
+	if cause, ok := r.(error); ok {
+		err.Cause = cause
+	}
+	if err.Callers == nil {
+		return err
+	}
+	pcs := *err.Callers
+	for i, pc := range pcs {
+		if fn := runtime.FuncForPC(pc - 1); fn == nil || fn.Name() != "runtime.gopanic" {
+			continue
+		}
+		var st stack = pcs[i+1:]
+		err.Callers = &st
+		if frame, _ := runtime.CallersFrames(st).Next(); frame.File != "" {
+			err.Caller = fmt.Sprintf("%s:%d", frame.File, frame.Line)
+			if DetailMode(detailMode.Load()) == DetailCaller {
+				err.Detail = err.Caller
+			}
+		}
+		break
+	}
+	return err
+}
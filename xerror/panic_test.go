@@ -0,0 +1,82 @@
+package xerror
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+)
+
+func panicking() {
+	panic("boom")
+}
+
+func recoverPanic(fn func()) (err *Error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = NewPanicError(r)
+		}
+	}()
+	fn()
+	return nil
+}
+
+func TestNewPanicError(t *testing.T) {
+	err := recoverPanic(panicking)
+	if err == nil {
+		t.Fatal("Expected panic error")
+	}
+
+	if err.Code != ErrCodeServerPanic {
+		t.Errorf("Expected code %s, got %s", ErrCodeServerPanic, err.Code)
+	}
+
+	if err.Message != "Server panic" || err.Info["panic"] != "boom" {
+		t.Errorf("Expected the default message and the panic value in info, got %q %v", err.Message, err.Info)
+	}
+	if b, _ := MarshalPublic(err); strings.Contains(string(b), "boom") {
+		t.Errorf("Expected public error to hide the panic value, got %s", b)
+	}
+
+	// The stack starts at the panicking function
+	frames := err.Frames()
+	if len(frames) == 0 || !strings.HasSuffix(frames[0].Function, ".panicking") {
+		t.Fatalf("Expected the first frame to be the panicking function, got %v", frames)
+	}
+
+	if err.Caller != fmt.Sprintf("%s:%d", frames[0].File, frames[0].Line) {
+		t.Errorf("Expected caller at the panic, got %s", err.Caller)
+	}
+}
+
+func TestNewPanicError_ErrorValue(t *testing.T) {
+	cause := errors.New("nil map")
+	err := recoverPanic(func() {
+		panic(cause)
+	})
+
+	if !errors.Is(err, cause) {
+		t.Error("Expected the panic value to be the cause")
+	}
+}
+
+func TestNewPanicError_OutsidePanic(t *testing.T) {
+	err := NewPanicError("not panicking")
+
+	if err.Code != ErrCodeServerPanic || len(err.Frames()) == 0 {
+		t.Errorf("Expected panic error with the current stack, got %v", err)
+	}
+}
+
+func TestNewPanicError_DetailNone(t *testing.T) {
+	defer SetDetailMode(DetailCaller)
+
+	if err := recoverPanic(panicking); err.Detail != err.Caller {
+		t.Errorf("Expected detail at the panic, got %s", err.Detail)
+	}
+
+	SetDetailMode(DetailNone)
+	if err := recoverPanic(panicking); err.Detail != "" || err.Caller == "" {
+		t.Errorf("Expected no detail with DetailNone, got %s", err.Detail)
+	}
+}
@@ -0,0 +1,254 @@
+package xerror
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"mime"
+	"net/http"
+	"strings"
+)
+
+// ProblemContentType is the media type of RFC 7807 problem details
+const ProblemContentType = "application/problem+json"
+
+// ProblemTypePrefix is prepended to the code in kebab-case to build the problem type URI
+var ProblemTypePrefix = "urn:problem-type:"
+
+var problemMembers = map[string]bool{
+	"type":     true,
+	"title":    true,
+	"status":   true,
+	"detail":   true,
+	"instance": true,
+}
+
+// Problem is an RFC 7807 problem details object, Extensions are serialized as top level members
+type Problem struct {
+	Type       string
+	Title      string
+	Status     int
+	Detail     string
+	Instance   string
+	Extensions map[string]any
+}
+
+func (p *Problem) MarshalJSON() ([]byte, error) {
+	m := make(map[string]any, len(p.Extensions)+5)
+	for k, v := range p.Extensions {
+		if !problemMembers[k] {
+			m[k] = v
+		}
+	}
+	m["type"] = p.Type
+	m["title"] = p.Title
+	m["status"] = p.Status
+	if p.Detail != "" {
+		m["detail"] = p.Detail
+	}
+	if p.Instance != "" {
+		m["instance"] = p.Instance
+	}
+	return json.Marshal(m)
+}
+
+func (p *Problem) UnmarshalJSON(b []byte) error {
+	var m map[string]json.RawMessage
+	if err := json.Unmarshal(b, &m); err != nil {
+		return err
+	}
+	*p = Problem{}
+	for k, raw := range m {
+		var err error
+		switch k {
+		case "type":
+			err = json.Unmarshal(raw, &p.Type)
+		case "title":
+			err = json.Unmarshal(raw, &p.Title)
+		case "status":
+			err = json.Unmarshal(raw, &p.Status)
+		case "detail":
+			err = json.Unmarshal(raw, &p.Detail)
+		case "instance":
+			err = json.Unmarshal(raw, &p.Instance)
+		default:
+			var v any
+			if err = json.Unmarshal(raw, &v); err == nil {
+				if p.Extensions == nil {
+					p.Extensions = make(map[string]any)
+				}
+				p.Extensions[k] = v
+			}
+		}
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// ToProblem converts xerror to problem details, the code and app name are kept as extension members along with Info
+// and the provenance of the chain
+func ToProblem(err *Error, instance string) *Problem {
+	title := err.Code
+	if info, ok := LookupCode(err.Code); ok && info.Message != "" {
+		title = info.Message
+	}
+	ext := make(map[string]any, len(err.Info)+2)
+	for k, v := range err.Info {
+		ext[k] = v
+	}
+	ext["code"] = err.Code
+	if err.AppName != "" {
+		ext["app_name"] = err.AppName
+	}
+	if len(err.Violations) > 0 {
+		ext["violations"] = err.Violations
+	}
+	if provenance := GetProvenance(err); len(provenance) > 0 {
+		ext["provenance"] = provenance
+	}
+	if len(err.Errors) > 0 {
+		ext["errors"] = err.Errors
+	}
+	return &Problem{
+		Type:       ProblemType(err.Code),
+		Title:      title,
+		Status:     HTTPStatus(err.Code),
+		Detail:     err.Message,
+		Instance:   instance,
+		Extensions: ext,
+	}
+}
+
+// FromProblem converts problem details to xerror. The code is taken from the "code" member, the type URI
+// or the status in that order. Instance and unknown members are kept in Info, use ReadResponse or Receive
+// to record the provenance of problems received from a peer
+func FromProblem(p *Problem) *Error {
+	code := problemCode(p)
+	msg := p.Detail
+	if msg == "" {
+		msg = p.Title
+	}
+	err := newRemoteError(code, msg)
+	for k, v := range p.Extensions {
+		switch k {
+		case "code":
+		case "app_name":
+			if s, ok := v.(string); ok {
+				err.AppName = s
+			}
+		case "violations":
+			if violations, ok := parseExtension[[]FieldViolation](v); ok {
+				err.Violations = violations
+			} else {
+				err = WithJSONInfo(k, v)(err)
+			}
+		case "errors":
+			if errs, ok := parseExtension[[]*Error](v); ok {
+				err.Errors = errs
+			} else {
+				err = WithJSONInfo(k, v)(err)
+			}
+		case "provenance":
+			if provenance, ok := parseExtension[[]Hop](v); ok {
+				err.Provenance = provenance
+			} else {
+				err = WithJSONInfo(k, v)(err)
+			}
+		default:
+			err = WithJSONInfo(k, v)(err)
+		}
+	}
+	if p.Instance != "" {
+		err = WithJSONInfo("instance", p.Instance)(err)
+	}
+	return err
+}
+
+// ParseProblem decodes an application/problem+json body into xerror
+func ParseProblem(data []byte) (*Error, error) {
+	var p Problem
+	if err := json.Unmarshal(data, &p); err != nil {
+		return nil, err
+	}
+	return FromProblem(&p), nil
+}
+
+// ReadResponse decodes the error of an HTTP response from a peer and appends the local service to its provenance,
+// the local hop records the caller of ReadResponse. Problem details and xerror JSON bodies are decoded, any other
+// body is used as the message of the code mapped from the status. nil is returned for a status below 400
+func ReadResponse(ctx context.Context, resp *http.Response) *Error {
+	if resp.StatusCode < http.StatusBadRequest {
+		return nil
+	}
+	c := caller(2)
+	body, _ := io.ReadAll(resp.Body)
+	mediaType, _, _ := mime.ParseMediaType(resp.Header.Get("Content-Type"))
+	switch mediaType {
+	case ProblemContentType:
+		if err, pErr := ParseProblem(body); pErr == nil {
+			return ReceiveAt(ctx, err, c)
+		}
+	case "application/json":
+		var err Error
+		if json.Unmarshal(body, &err) == nil && err.Code != "" {
+			return ReceiveAt(ctx, &err, c)
+		}
+	}
+	msg := strings.TrimSpace(string(body))
+	if msg == "" {
+		msg = http.StatusText(resp.StatusCode)
+	}
+	err := newRemoteError(problemCode(&Problem{Status: resp.StatusCode}), msg)
+	return ReceiveAt(ctx, err, c)
+}
+
+// WriteProblem writes err as application/problem+json with the http status of its code
+func WriteProblem(w http.ResponseWriter, err *Error, instance string) error {
+	p := ToProblem(err, instance)
+	b, mErr := json.Marshal(p)
+	if mErr != nil {
+		return mErr
+	}
+	w.Header().Set("Content-Type", ProblemContentType)
+	w.WriteHeader(p.Status)
+	_, wErr := w.Write(b)
+	return wErr
+}
+
+// ProblemType returns the problem type URI of code
+func ProblemType(code string) string {
+	return ProblemTypePrefix + strings.ToLower(strings.ReplaceAll(code, "_", "-"))
+}
+
+func problemCode(p *Problem) string {
+	if code, ok := p.Extensions["code"].(string); ok && code != "" {
+		return code
+	}
+	if strings.HasPrefix(p.Type, ProblemTypePrefix) && len(p.Type) > len(ProblemTypePrefix) {
+		return strings.ToUpper(strings.ReplaceAll(strings.TrimPrefix(p.Type, ProblemTypePrefix), "-", "_"))
+	}
+	if p.Status >= http.StatusInternalServerError {
+		return ErrCodePartnerInternalError
+	}
+	for _, info := range RegisteredCodes() {
+		if info.HTTPStatus == p.Status {
+			return info.Code
+		}
+	}
+	return ErrCodePartnerBadResponseError
+}
+
+// parseExtension converts a decoded extension member to T
+func parseExtension[T any](v any) (T, bool) {
+	var out T
+	b, err := json.Marshal(v)
+	if err != nil {
+		return out, false
+	}
+	if err = json.Unmarshal(b, &out); err != nil {
+		return out, false
+	}
+	return out, true
+}
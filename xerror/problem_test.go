@@ -0,0 +1,186 @@
+package xerror
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestProblemType(t *testing.T) {
+	expected := ProblemTypePrefix + "rate-limit-exceeded"
+	if pt := ProblemType(ErrCodeRateLimitExceeded); pt != expected {
+		t.Errorf("Expected problem type %s, got %s", expected, pt)
+	}
+}
+
+func TestToProblem(t *testing.T) {
+	err := NewError(ErrCodeNotFound,
+		WithMessage("user u-1 not found"),
+		WithJSONInfo("user_id", "u-1"),
+		WithJSONInfo("status", "ignored"),
+	)
+	err.AppName = "user-service"
+
+	p := ToProblem(err, "/users/u-1")
+
+	if p.Type != ProblemType(ErrCodeNotFound) {
+		t.Errorf("Expected type %s, got %s", ProblemType(ErrCodeNotFound), p.Type)
+	}
+
+	if p.Title != "Not found" {
+		t.Errorf("Expected title from registry, got %s", p.Title)
+	}
+
+	if p.Status != http.StatusNotFound {
+		t.Errorf("Expected status %d, got %d", http.StatusNotFound, p.Status)
+	}
+
+	if p.Detail != "user u-1 not found" {
+		t.Errorf("Expected detail from message, got %s", p.Detail)
+	}
+
+	if p.Instance != "/users/u-1" {
+		t.Errorf("Expected instance /users/u-1, got %s", p.Instance)
+	}
+
+	b, mErr := json.Marshal(p)
+	if mErr != nil {
+		t.Fatalf("Failed to marshal problem: %v", mErr)
+	}
+
+	var m map[string]any
+	if uErr := json.Unmarshal(b, &m); uErr != nil {
+		t.Fatalf("Failed to unmarshal problem: %v", uErr)
+	}
+
+	expected := map[string]any{
+		"type":     ProblemType(ErrCodeNotFound),
+		"title":    "Not found",
+		"status":   float64(http.StatusNotFound),
+		"detail":   "user u-1 not found",
+		"instance": "/users/u-1",
+		"code":     ErrCodeNotFound,
+		"app_name": "user-service",
+		"user_id":  "u-1",
+	}
+	for k, v := range expected {
+		if m[k] != v {
+			t.Errorf("Expected member %s to be %v, got %v", k, v, m[k])
+		}
+	}
+
+	// Unregistered code uses the code as title
+	if p := ToProblem(&Error{Code: "CUSTOM_CODE"}, ""); p.Title != "CUSTOM_CODE" {
+		t.Errorf("Expected title CUSTOM_CODE, got %s", p.Title)
+	}
+}
+
+func TestParseProblem(t *testing.T) {
+	tests := []struct {
+		name         string
+		body         string
+		expectCode   string
+		expectMsg    string
+		expectApp    string
+		expectInfo   map[string]any
+		expectErrors bool
+	}{
+		{
+			name:       "xerror problem",
+			body:       `{"type":"urn:problem-type:not-found","title":"Not found","status":404,"detail":"user not found","instance":"/users/1","code":"NOT_FOUND","app_name":"user-service","user_id":"1"}`,
+			expectCode: ErrCodeNotFound,
+			expectMsg:  "user not found",
+			expectApp:  "user-service",
+			expectInfo: map[string]any{"user_id": "1", "instance": "/users/1"},
+		},
+		{
+			name:       "code from type",
+			body:       `{"type":"urn:problem-type:already-exists","title":"Already exists","status":409}`,
+			expectCode: ErrCodeAlreadyExists,
+			expectMsg:  "Already exists",
+		},
+		{
+			name:       "partner client error",
+			body:       `{"type":"https://example.com/probs/out-of-credit","title":"You do not have enough credit.","status":403,"balance":30}`,
+			expectCode: ErrCodePermissionDenied,
+			expectMsg:  "You do not have enough credit.",
+			expectInfo: map[string]any{"balance": float64(30)},
+		},
+		{
+			name:       "partner server error",
+			body:       `{"type":"about:blank","title":"Service Unavailable","status":503}`,
+			expectCode: ErrCodePartnerInternalError,
+			expectMsg:  "Service Unavailable",
+		},
+		{
+			name:       "partner unknown status",
+			body:       `{"type":"about:blank","title":"Teapot","status":418}`,
+			expectCode: ErrCodePartnerBadResponseError,
+			expectMsg:  "Teapot",
+		},
+		{
+			name:         "invalid json",
+			body:         `not-json`,
+			expectErrors: true,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			err, pErr := ParseProblem([]byte(tc.body))
+			if tc.expectErrors {
+				if pErr == nil {
+					t.Error("Expected parse error, got nil")
+				}
+				return
+			}
+			if pErr != nil {
+				t.Fatalf("Expected no parse error, got %v", pErr)
+			}
+
+			if err.Code != tc.expectCode {
+				t.Errorf("Expected code %s, got %s", tc.expectCode, err.Code)
+			}
+
+			if err.Message != tc.expectMsg {
+				t.Errorf("Expected message %q, got %q", tc.expectMsg, err.Message)
+			}
+
+			if err.AppName != tc.expectApp && tc.expectApp != "" {
+				t.Errorf("Expected app name %s, got %s", tc.expectApp, err.AppName)
+			}
+
+			for k, v := range tc.expectInfo {
+				if err.Info[k] != v {
+					t.Errorf("Expected info %s to be %v, got %v", k, v, err.Info[k])
+				}
+			}
+		})
+	}
+}
+
+func TestWriteProblem(t *testing.T) {
+	w := httptest.NewRecorder()
+	err := WriteProblem(w, NewError(ErrCodeRateLimitExceeded), "/orders")
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if w.Code != http.StatusTooManyRequests {
+		t.Errorf("Expected status %d, got %d", http.StatusTooManyRequests, w.Code)
+	}
+
+	if ct := w.Header().Get("Content-Type"); ct != ProblemContentType {
+		t.Errorf("Expected content type %s, got %s", ProblemContentType, ct)
+	}
+
+	parsed, pErr := ParseProblem(w.Body.Bytes())
+	if pErr != nil {
+		t.Fatalf("Failed to parse written problem: %v", pErr)
+	}
+
+	if parsed.Code != ErrCodeRateLimitExceeded {
+		t.Errorf("Expected code %s, got %s", ErrCodeRateLimitExceeded, parsed.Code)
+	}
+}
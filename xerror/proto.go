@@ -0,0 +1,202 @@
+package xerror
+
+import (
+	"encoding/json"
+	"errors"
+	"time"
+
+	"github.com/kurzgesagtz/xgo/xerror/xerrorpb"
+
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/structpb"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// MarshalProto encodes err as xerrorpb.Error, unlike JSON the timestamp keeps nanoseconds
+func MarshalProto(err *Error) ([]byte, error) {
+	pb, e := ToProto(err)
+	if e != nil {
+		return nil, e
+	}
+	return proto.Marshal(pb)
+}
+
+// UnmarshalProto decodes an xerror encoded by MarshalProto
+func UnmarshalProto(b []byte) (*Error, error) {
+	var pb xerrorpb.Error
+	if err := proto.Unmarshal(b, &pb); err != nil {
+		return nil, err
+	}
+	return FromProto(&pb), nil
+}
+
+// ToProto converts err to its protobuf message, Info and violation values are converted through JSON
+// so they must be JSON serializable. Provenance is the provenance of the chain, see GetProvenance,
+// and Errors are encoded recursively
+func ToProto(err *Error) (*xerrorpb.Error, error) {
+	if err == nil {
+		return nil, nil
+	}
+	pb := &xerrorpb.Error{
+		Code:      err.Code,
+		Message:   err.Message,
+		Caller:    err.Caller,
+		Detail:    err.Detail,
+		AppName:   err.AppName,
+		Timestamp: timestamppb.New(time.Time(err.Timestamp)),
+	}
+	if len(err.Info) > 0 {
+		info, e := toProtoStruct(err.Info)
+		if e != nil {
+			return nil, e
+		}
+		pb.Info = info
+	}
+	for _, f := range err.Frames() {
+		pb.Frames = append(pb.Frames, &xerrorpb.Frame{
+			Function: f.Function,
+			File:     f.File,
+			Line:     int32(f.Line),
+		})
+	}
+	for _, v := range err.Violations {
+		value, e := toProtoValue(v.Value)
+		if e != nil {
+			return nil, e
+		}
+		pb.Violations = append(pb.Violations, &xerrorpb.FieldViolation{
+			Field:   v.Field,
+			Rule:    v.Rule,
+			Message: v.Message,
+			Value:   value,
+		})
+	}
+	for _, h := range GetProvenance(err) {
+		pb.Provenance = append(pb.Provenance, &xerrorpb.Hop{
+			AppName:   h.AppName,
+			Caller:    h.Caller,
+			Timestamp: timestamppb.New(time.Time(h.Timestamp)),
+			TraceId:   h.TraceID,
+		})
+	}
+	for _, child := range err.Errors {
+		childPb, e := ToProto(child)
+		if e != nil {
+			return nil, e
+		}
+		pb.Errors = append(pb.Errors, childPb)
+	}
+	cause, e := toProtoCause(err.Cause)
+	if e != nil {
+		return nil, e
+	}
+	pb.Cause = cause
+	return pb, nil
+}
+
+// FromProto converts the protobuf message back to xerror, the stack is available through Frames
+func FromProto(pb *xerrorpb.Error) *Error {
+	if pb == nil {
+		return nil
+	}
+	err := &Error{
+		Code:      pb.GetCode(),
+		Message:   pb.GetMessage(),
+		Caller:    pb.GetCaller(),
+		Detail:    pb.GetDetail(),
+		AppName:   pb.GetAppName(),
+		Cause:     fromProtoCause(pb.GetCause()),
+	}
+	if pb.GetTimestamp() != nil {
+		err.Timestamp = Timestamp(pb.GetTimestamp().AsTime())
+	}
+	if pb.GetInfo() != nil {
+		err.Info = pb.GetInfo().AsMap()
+	}
+	for _, f := range pb.GetFrames() {
+		err.frames = append(err.frames, Frame{
+			Function: f.GetFunction(),
+			File:     f.GetFile(),
+			Line:     int(f.GetLine()),
+		})
+	}
+	for _, v := range pb.GetViolations() {
+		violation := FieldViolation{
+			Field:   v.GetField(),
+			Rule:    v.GetRule(),
+			Message: v.GetMessage(),
+		}
+		if v.GetValue() != nil {
+			violation.Value = v.GetValue().AsInterface()
+		}
+		err.Violations = append(err.Violations, violation)
+	}
+	for _, h := range pb.GetProvenance() {
+		err.Provenance = append(err.Provenance, Hop{
+			AppName:   h.GetAppName(),
+			Caller:    h.GetCaller(),
+			Timestamp: Timestamp(h.GetTimestamp().AsTime()),
+			TraceID:   h.GetTraceId(),
+		})
+	}
+	for _, child := range pb.GetErrors() {
+		err.Errors = append(err.Errors, FromProto(child))
+	}
+	return err
+}
+
+func toProtoCause(err error) (*xerrorpb.Cause, error) {
+	if err == nil {
+		return nil, nil
+	}
+	if xErr, ok := err.(*Error); ok {
+		pb, e := ToProto(xErr)
+		if e != nil {
+			return nil, e
+		}
+		return &xerrorpb.Cause{Error: pb}, nil
+	}
+	cause, e := toProtoCause(errors.Unwrap(err))
+	if e != nil {
+		return nil, e
+	}
+	return &xerrorpb.Cause{Message: err.Error(), Cause: cause}, nil
+}
+
+func fromProtoCause(pb *xerrorpb.Cause) error {
+	if pb == nil {
+		return nil
+	}
+	if pb.GetError() != nil {
+		return FromProto(pb.GetError())
+	}
+	return &remoteCause{message: pb.GetMessage(), cause: fromProtoCause(pb.GetCause())}
+}
+
+// toProtoStruct normalizes info through JSON so structs and typed values are accepted by structpb
+func toProtoStruct(info map[string]any) (*structpb.Struct, error) {
+	b, err := json.Marshal(info)
+	if err != nil {
+		return nil, err
+	}
+	var m map[string]any
+	if err = json.Unmarshal(b, &m); err != nil {
+		return nil, err
+	}
+	return structpb.NewStruct(m)
+}
+
+func toProtoValue(v any) (*structpb.Value, error) {
+	if v == nil {
+		return nil, nil
+	}
+	b, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+	var normalized any
+	if err = json.Unmarshal(b, &normalized); err != nil {
+		return nil, err
+	}
+	return structpb.NewValue(normalized)
+}
@@ -0,0 +1,117 @@
+package xerror
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+)
+
+func TestProtoRoundTrip(t *testing.T) {
+	type user struct {
+		ID   string `json:"id"`
+		Name string `json:"name"`
+	}
+	root := errors.New("connection refused")
+	cause := NewError(ErrCodeServiceInternalError, WithCause(fmt.Errorf("dial: %w", root)))
+	err := NewError(ErrCodeInvalidRequest,
+		WithMessage("invalid user"),
+		WithJSONInfo("user", user{ID: "u-1", Name: "John"}),
+		WithJSONInfo("attempt", 2),
+		WithFieldViolation("email", "email", "email is invalid", "john@"),
+		WithCause(cause),
+		WithTimestamp(time.Date(2024, 1, 2, 3, 4, 5, 123456789, time.UTC)),
+	)
+	err.Provenance = []Hop{{AppName: "user-service", TraceID: "t-1", Timestamp: err.Timestamp}}
+
+	b, mErr := MarshalProto(err)
+	if mErr != nil {
+		t.Fatalf("Expected no error, got %v", mErr)
+	}
+
+	result, uErr := UnmarshalProto(b)
+	if uErr != nil {
+		t.Fatalf("Expected no error, got %v", uErr)
+	}
+
+	if result.Code != err.Code || result.Message != err.Message || result.Caller != err.Caller ||
+		result.Detail != err.Detail || result.AppName != err.AppName {
+		t.Errorf("Expected fields to round trip, got %+v", result)
+	}
+
+	// Nanoseconds are kept
+	if !time.Time(result.Timestamp).Equal(time.Time(err.Timestamp)) {
+		t.Errorf("Expected timestamp %v, got %v", time.Time(err.Timestamp), time.Time(result.Timestamp))
+	}
+
+	if u, ok := result.Info["user"].(map[string]any); !ok || u["name"] != "John" {
+		t.Errorf("Expected struct info as map, got %v", result.Info["user"])
+	}
+
+	if result.Info["attempt"] != float64(2) {
+		t.Errorf("Expected info attempt 2, got %v", result.Info["attempt"])
+	}
+
+	if len(result.Violations) != 1 || result.Violations[0].Field != "email" || result.Violations[0].Value != "john@" {
+		t.Errorf("Expected violations to round trip, got %v", result.Violations)
+	}
+
+	if len(result.Provenance) != 1 || result.Provenance[0].TraceID != "t-1" {
+		t.Errorf("Expected provenance to round trip, got %v", result.Provenance)
+	}
+
+	frames := result.Frames()
+	if len(frames) == 0 || len(frames) != len(err.Frames()) || frames[1] != err.Frames()[1] {
+		t.Errorf("Expected frames to round trip, got %v", frames)
+	}
+
+	// Cause chain
+	if !errors.Is(result, &Error{Code: ErrCodeServiceInternalError}) {
+		t.Error("Expected xerror cause to be restored")
+	}
+	if result.Error() != err.Error() {
+		t.Errorf("Expected %q, got %q", err.Error(), result.Error())
+	}
+}
+
+func TestProtoRoundTrip_Errors(t *testing.T) {
+	err := AsError(Join(NewError(ErrCodeNotFound, WithMessage("user not found")), NewError(ErrCodeInternalError)))
+
+	b, mErr := MarshalProto(err)
+	if mErr != nil {
+		t.Fatalf("Expected no error, got %v", mErr)
+	}
+	decoded, uErr := UnmarshalProto(b)
+	if uErr != nil {
+		t.Fatalf("Expected no error, got %v", uErr)
+	}
+	if decoded.Code != ErrCodeInternalError || len(decoded.Errors) != 2 {
+		t.Fatalf("Expected the dominant code with every error, got %v %v", decoded.Code, decoded.Errors)
+	}
+	if decoded.Errors[0].Code != ErrCodeNotFound || decoded.Errors[0].Message != "user not found" ||
+		decoded.Errors[1].Code != ErrCodeInternalError {
+		t.Errorf("Expected the aggregated errors in order, got %v", decoded.Errors)
+	}
+}
+
+func TestProtoNil(t *testing.T) {
+	pb, err := ToProto(nil)
+	if pb != nil || err != nil {
+		t.Errorf("Expected nil, got %v %v", pb, err)
+	}
+
+	if FromProto(nil) != nil {
+		t.Error("Expected nil")
+	}
+
+	if _, err := UnmarshalProto([]byte{0xff}); err == nil {
+		t.Error("Expected error for invalid payload")
+	}
+}
+
+func TestToProto_InvalidInfo(t *testing.T) {
+	err := NewError(ErrCodeInternalError, WithJSONInfo("fn", func() {}))
+	if _, e := MarshalProto(err); e == nil {
+		t.Error("Expected error for info which isn't JSON serializable")
+	}
+}
@@ -0,0 +1,70 @@
+package xerror
+
+import (
+	"context"
+	"go.opentelemetry.io/otel/trace"
+	"strings"
+)
+
+// Hop is a service an xerror went through, Provenance of xerror starts with the service which created it
+type Hop struct {
+	AppName   string    `json:"app_name"`
+	Caller    string    `json:"caller,omitempty"`
+	Timestamp Timestamp `json:"timestamp"`
+	TraceID   string    `json:"trace_id,omitempty"`
+}
+
+// Receive appends the local service to the provenance of err, call it when err is decoded from a gRPC or HTTP peer.
+// The origin of err is recorded first when it has no provenance yet, the local hop records the caller of Receive
+func Receive(ctx context.Context, err *Error) *Error {
+	return ReceiveAt(ctx, err, caller(2))
+}
+
+// ReceiveAt is Receive with the caller of the local hop, transports pass the call site of the application
+// or an empty caller when it is unknown
+func ReceiveAt(ctx context.Context, err *Error, caller string) *Error {
+	if err == nil {
+		return nil
+	}
+	if len(err.Provenance) == 0 && err.AppName != "" {
+		err.Provenance = append(err.Provenance, Hop{
+			AppName:   err.AppName,
+			Caller:    err.Caller,
+			Timestamp: err.Timestamp,
+		})
+	}
+	hop := Hop{
+		AppName:   AppName(),
+		Caller:    caller,
+		Timestamp: TimestampNow(),
+	}
+	if span := trace.SpanContextFromContext(ctx); span.IsValid() {
+		hop.TraceID = span.TraceID().String()
+	}
+	err.Provenance = append(err.Provenance, hop)
+	return err
+}
+
+// GetProvenance returns the provenance of the first xerror in the chain of err which has one
+func GetProvenance(err error) []Hop {
+	for err != nil {
+		apiErr, _ := lookupError(err)
+		if apiErr == nil {
+			return nil
+		}
+		if len(apiErr.Provenance) > 0 {
+			return apiErr.Provenance
+		}
+		err = apiErr.Cause
+	}
+	return nil
+}
+
+// FormatProvenance formats the app names of hops as "a -> b -> c"
+func FormatProvenance(hops []Hop) string {
+	names := make([]string, 0, len(hops))
+	for _, h := range hops {
+		names = append(names, h.AppName)
+	}
+	return strings.Join(names, " -> ")
+}
@@ -0,0 +1,241 @@
+package xerror
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"runtime"
+	"strings"
+	"testing"
+
+	"go.opentelemetry.io/otel/trace"
+)
+
+func TestReceive(t *testing.T) {
+	defer SetAppName(AppName())
+	SetAppName("gateway")
+
+	traceID, _ := trace.TraceIDFromHex("4bf92f3577b34da6a3ce929d0e0e4736")
+	spanID, _ := trace.SpanIDFromHex("00f067aa0ba902b7")
+	ctx := trace.ContextWithSpanContext(context.Background(), trace.NewSpanContext(trace.SpanContextConfig{
+		TraceID: traceID,
+		SpanID:  spanID,
+	}))
+
+	err := &Error{Code: ErrCodeNotFound, AppName: "user-service", Caller: "user.go:10"}
+	Receive(ctx, err)
+
+	if len(err.Provenance) != 2 {
+		t.Fatalf("Expected origin and local hops, got %v", err.Provenance)
+	}
+	if err.Provenance[0].AppName != "user-service" || err.Provenance[0].Caller != "user.go:10" {
+		t.Errorf("Expected origin hop of user-service, got %+v", err.Provenance[0])
+	}
+	local := err.Provenance[1]
+	if local.AppName != "gateway" {
+		t.Errorf("Expected local hop of gateway, got %s", local.AppName)
+	}
+	if local.TraceID != traceID.String() {
+		t.Errorf("Expected trace id %s, got %s", traceID, local.TraceID)
+	}
+	if !strings.Contains(local.Caller, "provenance_test.go") {
+		t.Errorf("Expected caller of Receive, got %s", local.Caller)
+	}
+
+	// Existing provenance is extended
+	Receive(context.Background(), err)
+	if len(err.Provenance) != 3 || err.Provenance[2].TraceID != "" {
+		t.Errorf("Expected a third hop without trace id, got %v", err.Provenance)
+	}
+
+	// Unknown origin is not recorded
+	err = Receive(context.Background(), &Error{Code: ErrCodeNotFound})
+	if len(err.Provenance) != 1 {
+		t.Errorf("Expected only the local hop, got %v", err.Provenance)
+	}
+
+	if Receive(context.Background(), nil) != nil {
+		t.Error("Expected nil for nil error")
+	}
+}
+
+func TestGetProvenance(t *testing.T) {
+	remote := &Error{Code: ErrCodeNotFound, Provenance: []Hop{{AppName: "a"}, {AppName: "b"}}}
+
+	if hops := GetProvenance(remote); len(hops) != 2 {
+		t.Errorf("Expected 2 hops, got %v", hops)
+	}
+
+	// Provenance of a wrapped remote error
+	if hops := GetProvenance(Wrap(remote, ErrCodeInternalError)); len(hops) != 2 {
+		t.Errorf("Expected provenance of the cause, got %v", hops)
+	}
+
+	if hops := GetProvenance(NewError(ErrCodeInternalError)); hops != nil {
+		t.Errorf("Expected nil provenance, got %v", hops)
+	}
+}
+
+func TestFormatProvenance(t *testing.T) {
+	hops := []Hop{{AppName: "a"}, {AppName: "b"}, {AppName: "c"}}
+	if s := FormatProvenance(hops); s != "a -> b -> c" {
+		t.Errorf("Expected %q, got %q", "a -> b -> c", s)
+	}
+}
+
+func TestProvenanceJSON(t *testing.T) {
+	err := NewError(ErrCodeNotFound)
+	err.Provenance = []Hop{{AppName: "a", TraceID: "t-1"}, {AppName: "b"}}
+
+	b, mErr := json.Marshal(err)
+	if mErr != nil {
+		t.Fatalf("Expected no error, got %v", mErr)
+	}
+	var decoded Error
+	if uErr := json.Unmarshal(b, &decoded); uErr != nil {
+		t.Fatalf("Expected no error, got %v", uErr)
+	}
+	if FormatProvenance(decoded.Provenance) != "a -> b" || decoded.Provenance[0].TraceID != "t-1" {
+		t.Errorf("Expected provenance to round trip, got %v", decoded.Provenance)
+	}
+
+	// Problem details round trip
+	p := ToProblem(err, "")
+	if FormatProvenance(FromProblem(p).Provenance) != "a -> b" {
+		t.Errorf("Expected provenance to round trip through problem details")
+	}
+
+	// Public profile strips provenance
+	if err.Public().Provenance != nil {
+		t.Error("Expected provenance to be stripped by the public profile")
+	}
+}
+
+func TestReadResponse(t *testing.T) {
+	defer SetAppName(AppName())
+	SetAppName("gateway")
+
+	testCases := []struct {
+		name             string
+		handler          http.HandlerFunc
+		expectNil        bool
+		expectCode       string
+		expectMessage    string
+		expectProvenance string
+		// remoteCaller is set when the peer sends the caller of the error
+		remoteCaller bool
+	}{
+		{
+			name: "problem details",
+			handler: func(w http.ResponseWriter, r *http.Request) {
+				err := NewError(ErrCodeNotFound, WithMessage("user not found"))
+				err.AppName = "user-service"
+				_ = WriteProblem(w, err, r.URL.Path)
+			},
+			expectCode:       ErrCodeNotFound,
+			expectMessage:    "user not found",
+			expectProvenance: "user-service -> gateway",
+		},
+		{
+			name: "xerror json",
+			handler: func(w http.ResponseWriter, r *http.Request) {
+				err := NewError(ErrCodeAlreadyExists, WithMessage("duplicated"))
+				err.AppName = "order-service"
+				err.Provenance = []Hop{{AppName: "stock-service"}, {AppName: "order-service"}}
+				w.Header().Set("Content-Type", "application/json; charset=utf-8")
+				w.WriteHeader(http.StatusConflict)
+				_ = json.NewEncoder(w).Encode(err)
+			},
+			expectCode:       ErrCodeAlreadyExists,
+			expectMessage:    "duplicated",
+			expectProvenance: "stock-service -> order-service -> gateway",
+			remoteCaller:     true,
+		},
+		{
+			name: "xerror json of an older version",
+			handler: func(w http.ResponseWriter, r *http.Request) {
+				w.Header().Set("Content-Type", "application/json")
+				w.WriteHeader(http.StatusNotFound)
+				_, _ = w.Write([]byte(`{"code":"NOT_FOUND","message":"user not found","app_name":"user-service",` +
+					`"timestamp":1714559400000,"callers":[4512345,4512400]}`))
+			},
+			expectCode:       ErrCodeNotFound,
+			expectMessage:    "user not found",
+			expectProvenance: "user-service -> gateway",
+		},
+		{
+			name: "plain text",
+			handler: func(w http.ResponseWriter, r *http.Request) {
+				http.Error(w, "upstream is down", http.StatusBadGateway)
+			},
+			expectCode:       ErrCodePartnerInternalError,
+			expectMessage:    "upstream is down",
+			expectProvenance: "gateway",
+		},
+		{
+			name: "success",
+			handler: func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(http.StatusOK)
+			},
+			expectNil: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			server := httptest.NewServer(tc.handler)
+			defer server.Close()
+
+			resp, err := http.Get(server.URL + "/users/1")
+			if err != nil {
+				t.Fatalf("Expected no error, got %v", err)
+			}
+			defer resp.Body.Close()
+
+			_, file, line, _ := runtime.Caller(0)
+			xErr := ReadResponse(context.Background(), resp)
+			if tc.expectNil {
+				if xErr != nil {
+					t.Errorf("Expected nil, got %v", xErr)
+				}
+				return
+			}
+			if xErr.Code != tc.expectCode {
+				t.Errorf("Expected code %s, got %s", tc.expectCode, xErr.Code)
+			}
+			if xErr.Message != tc.expectMessage {
+				t.Errorf("Expected message %q, got %q", tc.expectMessage, xErr.Message)
+			}
+			if s := FormatProvenance(xErr.Provenance); s != tc.expectProvenance {
+				t.Errorf("Expected provenance %q, got %q", tc.expectProvenance, s)
+			}
+			// The local hop is the call of ReadResponse, the origin only has the caller reported by the peer
+			if local := xErr.Provenance[len(xErr.Provenance)-1]; local.Caller != fmt.Sprintf("%s:%d", file, line+1) {
+				t.Errorf("Expected the caller of ReadResponse, got %s", local.Caller)
+			}
+			if !tc.remoteCaller && (xErr.Caller != "" || len(xErr.Provenance) > 1 && xErr.Provenance[0].Caller != "") {
+				t.Errorf("Expected no local caller for the origin, got %s %+v", xErr.Caller, xErr.Provenance[0])
+			}
+		})
+	}
+}
+
+func TestProvenance_WrappedEncoding(t *testing.T) {
+	remote := &Error{Code: ErrCodeNotFound, Provenance: []Hop{{AppName: "a"}, {AppName: "b"}}}
+	err := Wrap(remote, ErrCodeInternalError)
+
+	p := ToProblem(err, "")
+	if hops, _ := p.Extensions["provenance"].([]Hop); FormatProvenance(hops) != "a -> b" {
+		t.Errorf("Expected problem provenance of the cause, got %v", p.Extensions["provenance"])
+	}
+
+	pb, pErr := ToProto(err)
+	if pErr != nil {
+		t.Fatalf("Expected no error, got %v", pErr)
+	}
+	if hops := FromProto(pb).Provenance; FormatProvenance(hops) != "a -> b" {
+		t.Errorf("Expected proto provenance of the cause, got %v", hops)
+	}
+}
@@ -0,0 +1,210 @@
+package xerror
+
+import (
+	"encoding"
+	"encoding/json"
+	"reflect"
+	"strings"
+)
+
+// RedactedValue replaces the value of redacted Info keys and struct fields tagged with `xerror:"redact"`
+const RedactedValue = "[REDACTED]"
+
+const redactTagKey = "xerror"
+
+// Profile controls which parts of xerror are kept when it is serialized
+type Profile struct {
+	// Caller keeps Caller, and Detail when it is the default caller
+	Caller  bool
+	Stack   bool
+	AppName bool
+	Cause   bool
+	// Provenance keeps the services the xerror went through
+	Provenance bool
+	// ViolationValues keeps the rejected value of field violations
+	ViolationValues bool
+	// InfoAllowlist keeps only these Info keys, every key is kept when it is nil
+	InfoAllowlist []string
+	// RedactInfoKeys replaces the value of these keys with RedactedValue, it applies to nested maps and structs too
+	RedactInfoKeys []string
+}
+
+// InternalProfile keeps everything, it is intended for logs
+var InternalProfile = Profile{
+	Caller:          true,
+	Stack:           true,
+	AppName:         true,
+	Cause:           true,
+	Provenance:      true,
+	ViolationValues: true,
+}
+
+// PublicProfile strips internals, it is intended for responses to external clients
+var PublicProfile = Profile{
+	RedactInfoKeys: []string{"password", "secret", "token", "panic"},
+}
+
+// Apply returns a copy of err filtered by the profile, struct values in Info are converted to maps
+// so fields tagged with `xerror:"redact"` can be replaced
+func (p Profile) Apply(err *Error) *Error {
+	if err == nil {
+		return nil
+	}
+	cp := *err
+	if !p.Caller {
+		cp.Caller = ""
+		if err.Detail == err.Caller {
+			cp.Detail = ""
+		}
+	}
+	if !p.Stack {
+		cp.Callers = nil
+		cp.frames = nil
+	}
+	if !p.AppName {
+		cp.AppName = ""
+	}
+	if !p.Provenance {
+		cp.Provenance = nil
+	}
+	if !p.Cause {
+		cp.Cause = nil
+	} else if cause, ok := err.Cause.(*Error); ok {
+		cp.Cause = p.Apply(cause)
+	}
+	if len(err.Errors) > 0 {
+		cp.Errors = make([]*Error, len(err.Errors))
+		for i, e := range err.Errors {
+			cp.Errors[i] = p.Apply(e)
+		}
+	}
+	if len(err.Violations) > 0 {
+		cp.Violations = make([]FieldViolation, len(err.Violations))
+		copy(cp.Violations, err.Violations)
+		if !p.ViolationValues {
+			for i := range cp.Violations {
+				cp.Violations[i].Value = nil
+			}
+		}
+	}
+	cp.Info = p.applyInfo(err.Info)
+	return &cp
+}
+
+func (p Profile) applyInfo(info map[string]any) map[string]any {
+	if info == nil {
+		return nil
+	}
+	var allow map[string]bool
+	if p.InfoAllowlist != nil {
+		allow = make(map[string]bool, len(p.InfoAllowlist))
+		for _, k := range p.InfoAllowlist {
+			allow[k] = true
+		}
+	}
+	redact := make(map[string]bool, len(p.RedactInfoKeys))
+	for _, k := range p.RedactInfoKeys {
+		redact[strings.ToLower(k)] = true
+	}
+	out := make(map[string]any, len(info))
+	for k, v := range info {
+		if allow != nil && !allow[k] {
+			continue
+		}
+		if redact[strings.ToLower(k)] {
+			out[k] = RedactedValue
+			continue
+		}
+		out[k] = redactValue(reflect.ValueOf(v), redact)
+	}
+	if len(out) == 0 {
+		return nil
+	}
+	return out
+}
+
+func redactValue(v reflect.Value, redact map[string]bool) any {
+	if !v.IsValid() {
+		return nil
+	}
+	switch v.Interface().(type) {
+	case json.Marshaler, encoding.TextMarshaler:
+		return v.Interface()
+	}
+	switch v.Kind() {
+	case reflect.Pointer, reflect.Interface:
+		if v.IsNil() {
+			return nil
+		}
+		return redactValue(v.Elem(), redact)
+	case reflect.Struct:
+		out := make(map[string]any, v.NumField())
+		t := v.Type()
+		for i := 0; i < t.NumField(); i++ {
+			f := t.Field(i)
+			if !f.IsExported() {
+				continue
+			}
+			name, ok := jsonFieldName(f)
+			if !ok {
+				continue
+			}
+			if f.Tag.Get(redactTagKey) == "redact" || redact[strings.ToLower(name)] {
+				out[name] = RedactedValue
+				continue
+			}
+			out[name] = redactValue(v.Field(i), redact)
+		}
+		return out
+	case reflect.Map:
+		if v.Type().Key().Kind() != reflect.String {
+			return v.Interface()
+		}
+		out := make(map[string]any, v.Len())
+		iter := v.MapRange()
+		for iter.Next() {
+			k := iter.Key().String()
+			if redact[strings.ToLower(k)] {
+				out[k] = RedactedValue
+				continue
+			}
+			out[k] = redactValue(iter.Value(), redact)
+		}
+		return out
+	case reflect.Slice, reflect.Array:
+		if v.Kind() == reflect.Slice && v.IsNil() {
+			return nil
+		}
+		if v.Type().Elem().Kind() == reflect.Uint8 {
+			return v.Interface()
+		}
+		out := make([]any, v.Len())
+		for i := range out {
+			out[i] = redactValue(v.Index(i), redact)
+		}
+		return out
+	default:
+		return v.Interface()
+	}
+}
+
+func jsonFieldName(f reflect.StructField) (string, bool) {
+	tag := f.Tag.Get("json")
+	if tag == "-" {
+		return "", false
+	}
+	if name, _, _ := strings.Cut(tag, ","); name != "" {
+		return name, true
+	}
+	return f.Name, true
+}
+
+// Public returns a copy of err filtered by PublicProfile
+func (err *Error) Public() *Error {
+	return PublicProfile.Apply(err)
+}
+
+// MarshalPublic serializes err with PublicProfile, use it for responses to external clients
+func MarshalPublic(err *Error) ([]byte, error) {
+	return json.Marshal(PublicProfile.Apply(err))
+}
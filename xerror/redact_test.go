@@ -0,0 +1,189 @@
+package xerror
+
+import (
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+)
+
+type redactTestAccount struct {
+	ID       string `json:"id"`
+	Password string `json:"password"`
+	PIN      string `json:"pin" xerror:"redact"`
+	Internal string `json:"-"`
+	private  string
+}
+
+func TestProfile_Apply(t *testing.T) {
+	err := NewError(ErrCodeInvalidRequest,
+		WithMessage("invalid account"),
+		WithCause(errors.New("sql: constraint failed")),
+		WithJSONInfo("account", redactTestAccount{ID: "a-1", Password: "p", PIN: "1234", Internal: "x", private: "y"}),
+		WithJSONInfo("token", "t-1"),
+		WithJSONInfo("request_id", "r-1"),
+		WithFieldViolation("password", "min", "password is too short", "abc"),
+	)
+	err.AppName = "account-service"
+
+	public := PublicProfile.Apply(err)
+
+	if public == err {
+		t.Fatal("Expected a copy")
+	}
+
+	if public.Caller != "" || public.Detail != "" || public.Callers != nil || public.AppName != "" || public.Cause != nil {
+		t.Errorf("Expected internals to be stripped, got %+v", public)
+	}
+
+	if public.Code != err.Code || public.Message != err.Message {
+		t.Errorf("Expected code and message to be kept, got %s: %s", public.Code, public.Message)
+	}
+
+	if public.Info["token"] != RedactedValue {
+		t.Errorf("Expected token to be redacted, got %v", public.Info["token"])
+	}
+
+	if public.Info["request_id"] != "r-1" {
+		t.Errorf("Expected request_id to be kept, got %v", public.Info["request_id"])
+	}
+
+	account, ok := public.Info["account"].(map[string]any)
+	if !ok {
+		t.Fatalf("Expected struct to be converted to map, got %T", public.Info["account"])
+	}
+
+	expected := map[string]any{
+		"id":       "a-1",
+		"password": RedactedValue,
+		"pin":      RedactedValue,
+	}
+	if len(account) != len(expected) {
+		t.Errorf("Expected %d account fields, got %v", len(expected), account)
+	}
+	for k, v := range expected {
+		if account[k] != v {
+			t.Errorf("Expected account %s to be %v, got %v", k, v, account[k])
+		}
+	}
+
+	if public.Violations[0].Value != nil {
+		t.Errorf("Expected rejected value to be stripped, got %v", public.Violations[0].Value)
+	}
+
+	// The original error is untouched
+	if err.Info["token"] != "t-1" || err.Violations[0].Value != "abc" || err.Caller == "" {
+		t.Error("Expected the original error to be untouched")
+	}
+
+	// Internal profile keeps everything
+	internal := InternalProfile.Apply(err)
+	if internal.Caller != err.Caller || internal.Cause != err.Cause || internal.AppName != err.AppName {
+		t.Error("Expected internal profile to keep internals")
+	}
+	if internal.Violations[0].Value != "abc" {
+		t.Errorf("Expected rejected value to be kept, got %v", internal.Violations[0].Value)
+	}
+
+	if p := PublicProfile.Apply(nil); p != nil {
+		t.Errorf("Expected nil for nil error, got %v", p)
+	}
+}
+
+func TestProfile_InfoAllowlist(t *testing.T) {
+	err := NewError(ErrCodeNotFound,
+		WithJSONInfo("user_id", "u-1"),
+		WithJSONInfo("query", "SELECT 1"),
+	)
+
+	p := Profile{InfoAllowlist: []string{"user_id"}}
+	result := p.Apply(err)
+
+	if len(result.Info) != 1 || result.Info["user_id"] != "u-1" {
+		t.Errorf("Expected only allowed keys, got %v", result.Info)
+	}
+
+	// Nothing allowed drops Info
+	p = Profile{InfoAllowlist: []string{}}
+	if result := p.Apply(err); result.Info != nil {
+		t.Errorf("Expected nil Info, got %v", result.Info)
+	}
+}
+
+func TestProfile_NestedRedaction(t *testing.T) {
+	err := NewError(ErrCodeInternalError,
+		WithJSONInfo("request", map[string]any{
+			"headers": map[string]string{"Token": "t-1", "Accept": "application/json"},
+			"items":   []any{map[string]any{"secret": "s"}},
+		}),
+	)
+
+	result := PublicProfile.Apply(err)
+	b, mErr := json.Marshal(result)
+	if mErr != nil {
+		t.Fatalf("Failed to marshal error: %v", mErr)
+	}
+
+	s := string(b)
+	if strings.Contains(s, "t-1") || strings.Contains(s, `"s"`) {
+		t.Errorf("Expected nested values to be redacted, got %s", s)
+	}
+
+	if !strings.Contains(s, "application/json") {
+		t.Errorf("Expected other values to be kept, got %s", s)
+	}
+}
+
+func TestMarshalPublic(t *testing.T) {
+	err := NewError(ErrCodeNotFound, WithMessage("user not found"))
+
+	b, mErr := MarshalPublic(err)
+	if mErr != nil {
+		t.Fatalf("Failed to marshal error: %v", mErr)
+	}
+
+	var m map[string]any
+	if uErr := json.Unmarshal(b, &m); uErr != nil {
+		t.Fatalf("Failed to unmarshal error: %v", uErr)
+	}
+
+	for _, key := range []string{"caller", "detail", "callers", "app_name"} {
+		if _, ok := m[key]; ok {
+			t.Errorf("Expected %s to be stripped", key)
+		}
+	}
+
+	if m["message"] != "user not found" {
+		t.Errorf("Expected message to be kept, got %v", m["message"])
+	}
+
+	if pub := err.Public(); pub.Caller != "" {
+		t.Errorf("Expected Public to strip caller, got %s", pub.Caller)
+	}
+}
+
+func TestMarshalPublic_DecodedError(t *testing.T) {
+	remote := Wrap(NewError(ErrCodeNotFound), ErrCodeInternalError)
+	b, err := json.Marshal(remote)
+	if err != nil {
+		t.Fatalf("Failed to marshal error: %v", err)
+	}
+	decoded := &Error{}
+	if err = json.Unmarshal(b, decoded); err != nil {
+		t.Fatalf("Failed to unmarshal error: %v", err)
+	}
+	if len(decoded.Frames()) == 0 {
+		t.Fatal("Expected decoded error to keep its frames")
+	}
+
+	b, err = MarshalPublic(decoded)
+	if err != nil {
+		t.Fatalf("Failed to marshal error: %v", err)
+	}
+	if strings.Contains(string(b), "callers") {
+		t.Errorf("Expected decoded frames to be stripped, got %s", b)
+	}
+	if frames := InternalProfile.Apply(decoded).Frames(); len(frames) == 0 {
+		t.Error("Expected InternalProfile to keep decoded frames")
+	}
+}
@@ -0,0 +1,174 @@
+package xerror
+
+import (
+	"errors"
+	"fmt"
+	"go.uber.org/zap/zapcore"
+	"google.golang.org/grpc/codes"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// RetryAfterInfoKey is the Info key holding the delay in seconds before the request can be retried, see WithRetryAfter
+const RetryAfterInfoKey = "retry_after"
+
+// StatusClientClosedRequest is the non-standard http status used when the client cancels the request
+const StatusClientClosedRequest = 499
+
+// CodeInfo is the metadata of an error code
+type CodeInfo struct {
+	Code       string
+	HTTPStatus int
+	GRPCCode   codes.Code
+	// Message is used when the xerror is created without WithMessage
+	Message   string
+	Retryable bool
+	LogLevel  zapcore.Level
+}
+
+type codeRegistry struct {
+	mu    sync.RWMutex
+	codes map[string]CodeInfo
+	order []string
+}
+
+var registry = &codeRegistry{
+	codes: make(map[string]CodeInfo),
+}
+
+func init() {
+	for _, info := range []CodeInfo{
+		{Code: ErrCodeUnauthorized, HTTPStatus: http.StatusUnauthorized, GRPCCode: codes.Unauthenticated, Message: "Unauthorized", LogLevel: zapcore.WarnLevel},
+		{Code: ErrCodePermissionDenied, HTTPStatus: http.StatusForbidden, GRPCCode: codes.PermissionDenied, Message: "Permission denied", LogLevel: zapcore.WarnLevel},
+		{Code: ErrCodeInvalidRequest, HTTPStatus: http.StatusBadRequest, GRPCCode: codes.InvalidArgument, Message: "Invalid request", LogLevel: zapcore.WarnLevel},
+		{Code: ErrCodeNotFound, HTTPStatus: http.StatusNotFound, GRPCCode: codes.NotFound, Message: "Not found", LogLevel: zapcore.InfoLevel},
+		{Code: ErrCodeNotImplement, HTTPStatus: http.StatusNotImplemented, GRPCCode: codes.Unimplemented, Message: "Not implemented", LogLevel: zapcore.ErrorLevel},
+		{Code: ErrCodeInternalError, HTTPStatus: http.StatusInternalServerError, GRPCCode: codes.Internal, Message: "Internal error", LogLevel: zapcore.ErrorLevel},
+		{Code: ErrCodePartnerInternalError, HTTPStatus: http.StatusBadGateway, GRPCCode: codes.Unavailable, Message: "Partner internal error", Retryable: true, LogLevel: zapcore.ErrorLevel},
+		{Code: ErrCodePartnerBadResponseError, HTTPStatus: http.StatusBadGateway, GRPCCode: codes.Internal, Message: "Partner bad response", LogLevel: zapcore.ErrorLevel},
+		{Code: ErrCodeServiceInternalError, HTTPStatus: http.StatusInternalServerError, GRPCCode: codes.Internal, Message: "Service internal error", LogLevel: zapcore.ErrorLevel},
+		{Code: ErrCodeServerPanic, HTTPStatus: http.StatusInternalServerError, GRPCCode: codes.Internal, Message: "Server panic", LogLevel: zapcore.ErrorLevel},
+		{Code: ErrCodeInvalidEnum, HTTPStatus: http.StatusBadRequest, GRPCCode: codes.InvalidArgument, Message: "Invalid enum", LogLevel: zapcore.WarnLevel},
+		{Code: ErrCodeClientRequestCanceled, HTTPStatus: StatusClientClosedRequest, GRPCCode: codes.Canceled, Message: "Request canceled", LogLevel: zapcore.InfoLevel},
+		{Code: ErrCodeClientRequestDeadlineExceed, HTTPStatus: http.StatusGatewayTimeout, GRPCCode: codes.DeadlineExceeded, Message: "Request deadline exceeded", Retryable: true, LogLevel: zapcore.WarnLevel},
+		{Code: ErrCodeAlreadyExists, HTTPStatus: http.StatusConflict, GRPCCode: codes.AlreadyExists, Message: "Already exists", LogLevel: zapcore.WarnLevel},
+		{Code: ErrCodeRateLimitExceeded, HTTPStatus: http.StatusTooManyRequests, GRPCCode: codes.ResourceExhausted, Message: "Rate limit exceeded", Retryable: true, LogLevel: zapcore.WarnLevel},
+	} {
+		MustRegisterCode(info)
+	}
+}
+
+// RegisterCode adds a code to the registry, registering the same code again with different metadata returns an xerror
+func RegisterCode(info CodeInfo) error {
+	if info.Code == "" {
+		return NewError(ErrCodeInvalidRequest, WithMessage("error code is empty"))
+	}
+	registry.mu.Lock()
+	exist, ok := registry.codes[info.Code]
+	if !ok {
+		registry.codes[info.Code] = info
+		registry.order = append(registry.order, info.Code)
+	}
+	registry.mu.Unlock()
+	if ok && exist != info {
+		return NewError(ErrCodeAlreadyExists, WithMessage(fmt.Sprintf("error code %s is already registered with different metadata", info.Code)))
+	}
+	return nil
+}
+
+// MustRegisterCode is like RegisterCode but panics on conflict, it is intended for package level registrations
+func MustRegisterCode(info CodeInfo) {
+	if err := RegisterCode(info); err != nil {
+		panic(err)
+	}
+}
+
+// LookupCode returns the metadata of a registered code
+func LookupCode(code string) (CodeInfo, bool) {
+	registry.mu.RLock()
+	defer registry.mu.RUnlock()
+	info, ok := registry.codes[code]
+	return info, ok
+}
+
+// GetCodeInfo returns the metadata of code, unregistered codes are treated as ErrCodeInternalError
+func GetCodeInfo(code string) CodeInfo {
+	if info, ok := LookupCode(code); ok {
+		return info
+	}
+	info, _ := LookupCode(ErrCodeInternalError)
+	info.Code = code
+	return info
+}
+
+// GetErrorCodeInfo returns the metadata of the code of err, non xerror is treated as ErrCodeInternalError
+func GetErrorCodeInfo(err error) CodeInfo {
+	return GetCodeInfo(GetErrorCode(err))
+}
+
+// RegisteredCodes returns all registered codes in registration order
+func RegisteredCodes() []CodeInfo {
+	registry.mu.RLock()
+	defer registry.mu.RUnlock()
+	infos := make([]CodeInfo, 0, len(registry.order))
+	for _, code := range registry.order {
+		infos = append(infos, registry.codes[code])
+	}
+	return infos
+}
+
+func HTTPStatus(code string) int {
+	return GetCodeInfo(code).HTTPStatus
+}
+
+func GRPCCode(code string) codes.Code {
+	return GetCodeInfo(code).GRPCCode
+}
+
+func IsRetryable(code string) bool {
+	return GetCodeInfo(code).Retryable
+}
+
+// RetryAfter returns the delay stored with WithRetryAfter by the first xerror of the chain of err which has one.
+// Seconds as number or string and duration strings like "1.5s" are accepted
+func RetryAfter(err error) (time.Duration, bool) {
+	var xErr *Error
+	for errors.As(err, &xErr) {
+		if d, ok := parseRetryAfter(xErr.Info[RetryAfterInfoKey]); ok {
+			return d, true
+		}
+		err = xErr.Cause
+	}
+	return 0, false
+}
+
+func parseRetryAfter(v any) (time.Duration, bool) {
+	var seconds float64
+	switch v := v.(type) {
+	case time.Duration:
+		return v, v >= 0
+	case float64:
+		seconds = v
+	case int:
+		seconds = float64(v)
+	case int64:
+		seconds = float64(v)
+	case string:
+		if d, err := time.ParseDuration(v); err == nil {
+			return d, d >= 0
+		}
+		f, err := strconv.ParseFloat(v, 64)
+		if err != nil {
+			return 0, false
+		}
+		seconds = f
+	default:
+		return 0, false
+	}
+	if seconds < 0 {
+		return 0, false
+	}
+	return time.Duration(seconds * float64(time.Second)), true
+}
@@ -0,0 +1,206 @@
+package xerror
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+
+	"go.uber.org/zap/zapcore"
+	"google.golang.org/grpc/codes"
+)
+
+func TestBuiltinCodesRegistered(t *testing.T) {
+	// Every built-in code must have metadata
+	constants := []string{
+		ErrCodeUnauthorized,
+		ErrCodePermissionDenied,
+		ErrCodeInvalidRequest,
+		ErrCodeNotFound,
+		ErrCodeNotImplement,
+		ErrCodeInternalError,
+		ErrCodePartnerInternalError,
+		ErrCodePartnerBadResponseError,
+		ErrCodeServiceInternalError,
+		ErrCodeServerPanic,
+		ErrCodeInvalidEnum,
+		ErrCodeClientRequestCanceled,
+		ErrCodeClientRequestDeadlineExceed,
+		ErrCodeAlreadyExists,
+		ErrCodeRateLimitExceeded,
+	}
+
+	for _, c := range constants {
+		info, ok := LookupCode(c)
+		if !ok {
+			t.Errorf("Expected code %s to be registered", c)
+			continue
+		}
+		if info.HTTPStatus == 0 {
+			t.Errorf("Expected http status for code %s", c)
+		}
+		if info.Message == "" {
+			t.Errorf("Expected default message for code %s", c)
+		}
+	}
+}
+
+func TestCodeLookup(t *testing.T) {
+	tests := []struct {
+		name       string
+		code       string
+		httpStatus int
+		grpcCode   codes.Code
+		retryable  bool
+	}{
+		{
+			name:       "not found",
+			code:       ErrCodeNotFound,
+			httpStatus: http.StatusNotFound,
+			grpcCode:   codes.NotFound,
+			retryable:  false,
+		},
+		{
+			name:       "rate limit",
+			code:       ErrCodeRateLimitExceeded,
+			httpStatus: http.StatusTooManyRequests,
+			grpcCode:   codes.ResourceExhausted,
+			retryable:  true,
+		},
+		{
+			name:       "unregistered code is internal",
+			code:       "UNREGISTERED_CODE",
+			httpStatus: http.StatusInternalServerError,
+			grpcCode:   codes.Internal,
+			retryable:  false,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			if s := HTTPStatus(tc.code); s != tc.httpStatus {
+				t.Errorf("Expected http status %d, got %d", tc.httpStatus, s)
+			}
+			if c := GRPCCode(tc.code); c != tc.grpcCode {
+				t.Errorf("Expected grpc code %v, got %v", tc.grpcCode, c)
+			}
+			if r := IsRetryable(tc.code); r != tc.retryable {
+				t.Errorf("Expected retryable %v, got %v", tc.retryable, r)
+			}
+			if info := GetCodeInfo(tc.code); info.Code != tc.code {
+				t.Errorf("Expected code %s, got %s", tc.code, info.Code)
+			}
+		})
+	}
+}
+
+func TestGetErrorCodeInfo(t *testing.T) {
+	info := GetErrorCodeInfo(NewError(ErrCodeNotFound))
+	if info.Code != ErrCodeNotFound {
+		t.Errorf("Expected code %s, got %s", ErrCodeNotFound, info.Code)
+	}
+
+	info = GetErrorCodeInfo(errors.New("standard error"))
+	if info.Code != ErrCodeInternalError {
+		t.Errorf("Expected code %s, got %s", ErrCodeInternalError, info.Code)
+	}
+}
+
+func TestRegisterCode(t *testing.T) {
+	info := CodeInfo{
+		Code:       "TEST_REGISTER_CODE",
+		HTTPStatus: http.StatusPaymentRequired,
+		GRPCCode:   codes.FailedPrecondition,
+		Message:    "Payment required",
+		LogLevel:   zapcore.WarnLevel,
+	}
+
+	if err := RegisterCode(info); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	// Registering the same metadata again is allowed
+	if err := RegisterCode(info); err != nil {
+		t.Errorf("Expected no error on identical registration, got %v", err)
+	}
+
+	// Conflicting metadata is rejected
+	conflict := info
+	conflict.HTTPStatus = http.StatusBadRequest
+	err := RegisterCode(conflict)
+	if !IsErrorCode(err, ErrCodeAlreadyExists) {
+		t.Errorf("Expected %s, got %v", ErrCodeAlreadyExists, err)
+	}
+
+	if got, _ := LookupCode(info.Code); got != info {
+		t.Errorf("Expected registered info %+v, got %+v", info, got)
+	}
+
+	// Empty code is rejected
+	if err := RegisterCode(CodeInfo{}); !IsErrorCode(err, ErrCodeInvalidRequest) {
+		t.Errorf("Expected %s, got %v", ErrCodeInvalidRequest, err)
+	}
+
+	// Registered codes keep registration order
+	all := RegisteredCodes()
+	if all[0].Code != ErrCodeUnauthorized {
+		t.Errorf("Expected first code %s, got %s", ErrCodeUnauthorized, all[0].Code)
+	}
+	if all[len(all)-1].Code != info.Code {
+		t.Errorf("Expected last code %s, got %s", info.Code, all[len(all)-1].Code)
+	}
+
+	// New errors get the default message
+	if msg := NewError(info.Code).Message; msg != info.Message {
+		t.Errorf("Expected default message %q, got %q", info.Message, msg)
+	}
+}
+
+func TestMustRegisterCode(t *testing.T) {
+	defer func() {
+		if r := recover(); r == nil {
+			t.Error("Expected MustRegisterCode to panic on conflict")
+		}
+	}()
+
+	MustRegisterCode(CodeInfo{Code: ErrCodeNotFound, HTTPStatus: http.StatusTeapot})
+}
+
+func TestRetryAfter(t *testing.T) {
+	testCases := []struct {
+		name     string
+		err      error
+		expect   time.Duration
+		expectOk bool
+	}{
+		{"option", NewError(ErrCodeRateLimitExceeded, WithRetryAfter(1500*time.Millisecond)), 1500 * time.Millisecond, true},
+		{"seconds string", NewError(ErrCodeRateLimitExceeded, WithJSONInfo(RetryAfterInfoKey, "2")), 2 * time.Second, true},
+		{"duration string", NewError(ErrCodeRateLimitExceeded, WithJSONInfo(RetryAfterInfoKey, "250ms")), 250 * time.Millisecond, true},
+		{"int", NewError(ErrCodeRateLimitExceeded, WithJSONInfo(RetryAfterInfoKey, 3)), 3 * time.Second, true},
+		{"cause", Wrap(NewError(ErrCodeRateLimitExceeded, WithRetryAfter(time.Second)), ErrCodeInternalError), time.Second, true},
+		{"invalid", NewError(ErrCodeRateLimitExceeded, WithJSONInfo(RetryAfterInfoKey, "soon")), 0, false},
+		{"negative", NewError(ErrCodeRateLimitExceeded, WithJSONInfo(RetryAfterInfoKey, -1)), 0, false},
+		{"missing", NewError(ErrCodeRateLimitExceeded), 0, false},
+		{"nil", nil, 0, false},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			d, ok := RetryAfter(tc.err)
+			if ok != tc.expectOk || d != tc.expect {
+				t.Errorf("Expected %v %v, got %v %v", tc.expect, tc.expectOk, d, ok)
+			}
+		})
+	}
+
+	// Survives JSON
+	b, _ := json.Marshal(NewError(ErrCodeRateLimitExceeded, WithRetryAfter(2*time.Second)))
+	var decoded Error
+	if err := json.Unmarshal(b, &decoded); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if d, ok := RetryAfter(&decoded); !ok || d != 2*time.Second {
+		t.Errorf("Expected 2s after JSON round trip, got %v %v", d, ok)
+	}
+}
@@ -0,0 +1,99 @@
+package xerror
+
+import (
+	"fmt"
+	"io"
+	"runtime"
+	"strings"
+	"sync/atomic"
+)
+
+const defaultStackDepth = 32
+
+var stackDepth atomic.Int32
+var stackTrim atomic.Bool
+
+func init() {
+	stackDepth.Store(defaultStackDepth)
+}
+
+// SetStackDepth sets the maximum number of frames captured by new xerror, default is 32
+func SetStackDepth(depth int) {
+	if depth < 0 {
+		depth = 0
+	}
+	stackDepth.Store(int32(depth))
+}
+
+// SetStackTrim removes runtime and testing frames when the stack is resolved
+func SetStackTrim(trim bool) {
+	stackTrim.Store(trim)
+}
+
+// Frame is a resolved stack frame
+type Frame struct {
+	Function string `json:"function"`
+	File     string `json:"file"`
+	Line     int    `json:"line"`
+}
+
+func (f Frame) String() string {
+	return fmt.Sprintf("%s\n\t%s:%d", f.Function, f.File, f.Line)
+}
+
+func (st *stack) frames() []Frame {
+	if st == nil || len(*st) == 0 {
+		return nil
+	}
+	trim := stackTrim.Load()
+	frames := make([]Frame, 0, len(*st))
+	it := runtime.CallersFrames(*st)
+	for {
+		f, more := it.Next()
+		if !trim || !isRuntimeFrame(f.Function) {
+			frames = append(frames, Frame{
+				Function: f.Function,
+				File:     f.File,
+				Line:     f.Line,
+			})
+		}
+		if !more {
+			break
+		}
+	}
+	return frames
+}
+
+func isRuntimeFrame(function string) bool {
+	return strings.HasPrefix(function, "runtime.") || strings.HasPrefix(function, "testing.")
+}
+
+// Frames returns the resolved stack, errors decoded from JSON return the frames of the remote stack
+func (err *Error) Frames() []Frame {
+	if err.Callers != nil {
+		return err.Callers.frames()
+	}
+	return err.frames
+}
+
+// Format implements fmt.Formatter, %+v prints the stack of err and its causes like github.com/pkg/errors
+func (err *Error) Format(s fmt.State, verb rune) {
+	switch verb {
+	case 'v':
+		if s.Flag('+') {
+			_, _ = fmt.Fprintf(s, "%s: %s", err.Code, err.Message)
+			for _, f := range err.Frames() {
+				_, _ = fmt.Fprintf(s, "\n%s", f)
+			}
+			if err.Cause != nil {
+				_, _ = fmt.Fprintf(s, "\ncaused by: %+v", err.Cause)
+			}
+			return
+		}
+		fallthrough
+	case 's':
+		_, _ = io.WriteString(s, err.Error())
+	case 'q':
+		_, _ = fmt.Fprintf(s, "%q", err.Error())
+	}
+}
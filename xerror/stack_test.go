@@ -0,0 +1,196 @@
+package xerror
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+)
+
+func TestError_Frames(t *testing.T) {
+	err := NewError(ErrCodeInternalError)
+
+	frames := err.Frames()
+	if len(frames) == 0 {
+		t.Fatal("Expected non-empty frames")
+	}
+
+	if !strings.HasSuffix(frames[1].Function, "TestError_Frames") {
+		t.Errorf("Expected the test function in the stack, got %s", frames[1].Function)
+	}
+
+	if !strings.HasSuffix(frames[1].File, "stack_test.go") || frames[1].Line == 0 {
+		t.Errorf("Expected file and line of the test, got %s:%d", frames[1].File, frames[1].Line)
+	}
+
+	// No stack
+	if frames := (&Error{}).Frames(); frames != nil {
+		t.Errorf("Expected nil frames, got %v", frames)
+	}
+}
+
+func TestSetStackTrim(t *testing.T) {
+	defer SetStackTrim(false)
+
+	err := NewError(ErrCodeInternalError)
+
+	hasRuntimeFrame := func(frames []Frame) bool {
+		for _, f := range frames {
+			if isRuntimeFrame(f.Function) {
+				return true
+			}
+		}
+		return false
+	}
+
+	if !hasRuntimeFrame(err.Frames()) {
+		t.Error("Expected runtime or testing frames without trimming")
+	}
+
+	SetStackTrim(true)
+	if hasRuntimeFrame(err.Frames()) {
+		t.Error("Expected runtime and testing frames to be trimmed")
+	}
+}
+
+func TestSetStackDepth(t *testing.T) {
+	defer SetStackDepth(defaultStackDepth)
+
+	SetStackDepth(2)
+	err := NewError(ErrCodeInternalError)
+	if len(*err.Callers) != 2 {
+		t.Errorf("Expected 2 callers, got %d", len(*err.Callers))
+	}
+
+	SetStackDepth(-1)
+	err = NewError(ErrCodeInternalError)
+	if len(*err.Callers) != 0 {
+		t.Errorf("Expected no callers, got %d", len(*err.Callers))
+	}
+}
+
+func TestError_Format(t *testing.T) {
+	cause := errors.New("connection refused")
+	err := NewError(ErrCodeInternalError, WithMessage("query failed"), WithCause(cause))
+
+	tests := []struct {
+		name   string
+		format string
+		check  func(s string) bool
+	}{
+		{
+			name:   "%s",
+			format: "%s",
+			check: func(s string) bool {
+				return s == "INTERNAL_ERROR: query failed: connection refused"
+			},
+		},
+		{
+			name:   "%v",
+			format: "%v",
+			check: func(s string) bool {
+				return s == "INTERNAL_ERROR: query failed: connection refused"
+			},
+		},
+		{
+			name:   "%q",
+			format: "%q",
+			check: func(s string) bool {
+				return s == `"INTERNAL_ERROR: query failed: connection refused"`
+			},
+		},
+		{
+			name:   "%+v",
+			format: "%+v",
+			check: func(s string) bool {
+				return strings.HasPrefix(s, "INTERNAL_ERROR: query failed\n") &&
+					strings.Contains(s, "TestError_Format") &&
+					strings.Contains(s, "stack_test.go:") &&
+					strings.HasSuffix(s, "\ncaused by: connection refused")
+			},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			s := fmt.Sprintf(tc.format, err)
+			if !tc.check(s) {
+				t.Errorf("Unexpected output for %s: %s", tc.format, s)
+			}
+		})
+	}
+}
+
+func TestError_MarshalJSONFrames(t *testing.T) {
+	err := NewError(ErrCodeInternalError)
+
+	b, mErr := json.Marshal(err)
+	if mErr != nil {
+		t.Fatalf("Failed to marshal error: %v", mErr)
+	}
+
+	var raw struct {
+		Callers []map[string]any `json:"callers"`
+	}
+	if uErr := json.Unmarshal(b, &raw); uErr != nil {
+		t.Fatalf("Failed to unmarshal callers: %v", uErr)
+	}
+
+	if len(raw.Callers) == 0 {
+		t.Fatal("Expected callers to be serialized")
+	}
+
+	for _, key := range []string{"function", "file", "line"} {
+		if _, ok := raw.Callers[0][key]; !ok {
+			t.Errorf("Expected frame to have %s", key)
+		}
+	}
+
+	// Frames survive the round trip
+	var decoded Error
+	if uErr := json.Unmarshal(b, &decoded); uErr != nil {
+		t.Fatalf("Failed to unmarshal error: %v", uErr)
+	}
+
+	if decoded.Callers != nil {
+		t.Error("Expected no raw callers after decoding")
+	}
+
+	frames := decoded.Frames()
+	expected := err.Frames()
+	if len(frames) != len(expected) {
+		t.Fatalf("Expected %d frames, got %d", len(expected), len(frames))
+	}
+
+	if frames[0] != expected[0] {
+		t.Errorf("Expected frame %+v, got %+v", expected[0], frames[0])
+	}
+}
+
+func TestError_UnmarshalJSONProgramCounters(t *testing.T) {
+	b, _ := json.Marshal(NewError(ErrCodeNotFound, WithMessage("user not found")))
+	var payload map[string]any
+	if err := json.Unmarshal(b, &payload); err != nil {
+		t.Fatalf("Failed to unmarshal payload: %v", err)
+	}
+	// Older versions wrote the program counters of the stack
+	payload["callers"] = []uint64{4512345, 4512400}
+	b, _ = json.Marshal(payload)
+
+	var decoded Error
+	if err := json.Unmarshal(b, &decoded); err != nil {
+		t.Fatalf("Expected program counters to be accepted, got %v", err)
+	}
+	if decoded.Code != ErrCodeNotFound || decoded.Message != "user not found" {
+		t.Errorf("Expected code and message to be kept, got %s %s", decoded.Code, decoded.Message)
+	}
+	if len(decoded.Frames()) != 0 {
+		t.Errorf("Expected program counters of another process to be dropped, got %v", decoded.Frames())
+	}
+
+	// Other shapes are still rejected
+	if err := json.Unmarshal([]byte(`{"code":"NOT_FOUND","callers":"main.go:1"}`), &decoded); err == nil {
+		t.Error("Expected error for invalid callers")
+	}
+}
@@ -0,0 +1,67 @@
+package xerror
+
+// FieldViolation describes a field which failed validation
+type FieldViolation struct {
+	// Field is the path of the field e.g. "address.city" or "items[0].quantity"
+	Field   string `json:"field"`
+	Rule    string `json:"rule,omitempty"`
+	Message string `json:"message,omitempty"`
+	Value   any    `json:"value,omitempty"`
+}
+
+// ValidationBuilder accumulates field violations and builds an ErrCodeInvalidRequest xerror
+type ValidationBuilder struct {
+	violations []FieldViolation
+}
+
+func NewValidationBuilder() *ValidationBuilder {
+	return &ValidationBuilder{
+		violations: make([]FieldViolation, 0),
+	}
+}
+
+func (b *ValidationBuilder) Add(field, rule, message string, value any) *ValidationBuilder {
+	return b.AddViolation(FieldViolation{
+		Field:   field,
+		Rule:    rule,
+		Message: message,
+		Value:   value,
+	})
+}
+
+func (b *ValidationBuilder) AddViolation(v ...FieldViolation) *ValidationBuilder {
+	b.violations = append(b.violations, v...)
+	return b
+}
+
+func (b *ValidationBuilder) HasViolations() bool {
+	return len(b.violations) > 0
+}
+
+func (b *ValidationBuilder) Violations() []FieldViolation {
+	return b.violations
+}
+
+// Err returns nil when there is no violation, otherwise an ErrCodeInvalidRequest xerror holding all violations
+func (b *ValidationBuilder) Err(fn ...ErrorOptionFunc) error {
+	if !b.HasViolations() {
+		return nil
+	}
+	return newError(ErrCodeInvalidRequest, append([]ErrorOptionFunc{WithFieldViolations(b.violations...)}, fn...)...)
+}
+
+// GetFieldViolations returns the field violations of xerror, violations of aggregated errors are concatenated
+func GetFieldViolations(err error) []FieldViolation {
+	apiErr, multiErr := lookupError(err)
+	if apiErr != nil {
+		return apiErr.Violations
+	}
+	if multiErr != nil {
+		var violations []FieldViolation
+		for _, e := range multiErr.errs {
+			violations = append(violations, GetFieldViolations(e)...)
+		}
+		return violations
+	}
+	return nil
+}
@@ -0,0 +1,85 @@
+package xerror
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+)
+
+func TestValidationBuilder(t *testing.T) {
+	// Test builder without violations
+	b := NewValidationBuilder()
+
+	if b.HasViolations() {
+		t.Error("Expected no violations")
+	}
+
+	if err := b.Err(); err != nil {
+		t.Errorf("Expected nil error without violations, got %v", err)
+	}
+
+	// Test builder with violations
+	b.Add("email", "email", "email is invalid", "not-an-email").
+		AddViolation(FieldViolation{Field: "items[0].quantity", Rule: "min", Message: "quantity must be at least 1", Value: 0})
+
+	if !b.HasViolations() {
+		t.Error("Expected violations")
+	}
+
+	err := b.Err(WithMessage("order is invalid"))
+	if !IsErrorCode(err, ErrCodeInvalidRequest) {
+		t.Fatalf("Expected code %s, got %v", ErrCodeInvalidRequest, err)
+	}
+
+	if GetErrorMessage(err) != "order is invalid" {
+		t.Errorf("Expected message %q, got %q", "order is invalid", GetErrorMessage(err))
+	}
+
+	violations := GetFieldViolations(err)
+	if len(violations) != 2 {
+		t.Fatalf("Expected 2 violations, got %d", len(violations))
+	}
+
+	if violations[0].Field != "email" || violations[1].Field != "items[0].quantity" {
+		t.Errorf("Expected violations in insertion order, got %+v", violations)
+	}
+}
+
+func TestGetFieldViolations(t *testing.T) {
+	if v := GetFieldViolations(errors.New("standard error")); v != nil {
+		t.Errorf("Expected nil violations for standard error, got %v", v)
+	}
+
+	if v := GetFieldViolations(NewError(ErrCodeInvalidRequest)); len(v) != 0 {
+		t.Errorf("Expected no violations, got %v", v)
+	}
+}
+
+func TestFieldViolationJSON(t *testing.T) {
+	err := NewError(ErrCodeInvalidRequest, WithFieldViolation("name", "required", "name is required", ""))
+
+	b, mErr := json.Marshal(err)
+	if mErr != nil {
+		t.Fatalf("Failed to marshal error: %v", mErr)
+	}
+
+	var decoded Error
+	if uErr := json.Unmarshal(b, &decoded); uErr != nil {
+		t.Fatalf("Failed to unmarshal error: %v", uErr)
+	}
+
+	if len(decoded.Violations) != 1 {
+		t.Fatalf("Expected 1 violation, got %d", len(decoded.Violations))
+	}
+
+	v := decoded.Violations[0]
+	if v.Field != "name" || v.Rule != "required" || v.Message != "name is required" {
+		t.Errorf("Expected violation to round trip, got %+v", v)
+	}
+
+	// Violations round trip through problem details too
+	parsed := FromProblem(ToProblem(err, ""))
+	if len(parsed.Violations) != 1 || parsed.Violations[0].Field != "name" {
+		t.Errorf("Expected violations to round trip through problem, got %+v", parsed.Violations)
+	}
+}
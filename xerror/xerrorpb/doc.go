@@ -0,0 +1,5 @@
+// Package xerrorpb holds the protobuf message of xerror.Error, use xerror.MarshalProto and xerror.UnmarshalProto
+// instead of building it by hand
+package xerrorpb
+
+//go:generate protoc --go_out=../.. --go_opt=paths=source_relative -I ../.. xerror/xerrorpb/xerror.proto
@@ -0,0 +1,525 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.6
+// 	protoc        v5.29.3
+// source: xerror/xerrorpb/xerror.proto
+
+package xerrorpb
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	structpb "google.golang.org/protobuf/types/known/structpb"
+	timestamppb "google.golang.org/protobuf/types/known/timestamppb"
+	reflect "reflect"
+	sync "sync"
+	unsafe "unsafe"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+// Error is the binary form of xerror.Error
+type Error struct {
+	state      protoimpl.MessageState `protogen:"open.v1"`
+	Code       string                 `protobuf:"bytes,1,opt,name=code,proto3" json:"code,omitempty"`
+	Message    string                 `protobuf:"bytes,2,opt,name=message,proto3" json:"message,omitempty"`
+	Caller     string                 `protobuf:"bytes,3,opt,name=caller,proto3" json:"caller,omitempty"`
+	Detail     string                 `protobuf:"bytes,4,opt,name=detail,proto3" json:"detail,omitempty"`
+	AppName    string                 `protobuf:"bytes,5,opt,name=app_name,json=appName,proto3" json:"app_name,omitempty"`
+	Info       *structpb.Struct       `protobuf:"bytes,6,opt,name=info,proto3" json:"info,omitempty"`
+	Timestamp  *timestamppb.Timestamp `protobuf:"bytes,7,opt,name=timestamp,proto3" json:"timestamp,omitempty"`
+	Frames     []*Frame               `protobuf:"bytes,8,rep,name=frames,proto3" json:"frames,omitempty"`
+	Violations []*FieldViolation      `protobuf:"bytes,9,rep,name=violations,proto3" json:"violations,omitempty"`
+	Provenance []*Hop                 `protobuf:"bytes,10,rep,name=provenance,proto3" json:"provenance,omitempty"`
+	Cause      *Cause                 `protobuf:"bytes,11,opt,name=cause,proto3" json:"cause,omitempty"`
+	// errors are the aggregated errors of xerror.MultiError.ToError
+	Errors        []*Error `protobuf:"bytes,12,rep,name=errors,proto3" json:"errors,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Error) Reset() {
+	*x = Error{}
+	mi := &file_xerror_xerrorpb_xerror_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Error) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Error) ProtoMessage() {}
+
+func (x *Error) ProtoReflect() protoreflect.Message {
+	mi := &file_xerror_xerrorpb_xerror_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Error.ProtoReflect.Descriptor instead.
+func (*Error) Descriptor() ([]byte, []int) {
+	return file_xerror_xerrorpb_xerror_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *Error) GetCode() string {
+	if x != nil {
+		return x.Code
+	}
+	return ""
+}
+
+func (x *Error) GetMessage() string {
+	if x != nil {
+		return x.Message
+	}
+	return ""
+}
+
+func (x *Error) GetCaller() string {
+	if x != nil {
+		return x.Caller
+	}
+	return ""
+}
+
+func (x *Error) GetDetail() string {
+	if x != nil {
+		return x.Detail
+	}
+	return ""
+}
+
+func (x *Error) GetAppName() string {
+	if x != nil {
+		return x.AppName
+	}
+	return ""
+}
+
+func (x *Error) GetInfo() *structpb.Struct {
+	if x != nil {
+		return x.Info
+	}
+	return nil
+}
+
+func (x *Error) GetTimestamp() *timestamppb.Timestamp {
+	if x != nil {
+		return x.Timestamp
+	}
+	return nil
+}
+
+func (x *Error) GetFrames() []*Frame {
+	if x != nil {
+		return x.Frames
+	}
+	return nil
+}
+
+func (x *Error) GetViolations() []*FieldViolation {
+	if x != nil {
+		return x.Violations
+	}
+	return nil
+}
+
+func (x *Error) GetProvenance() []*Hop {
+	if x != nil {
+		return x.Provenance
+	}
+	return nil
+}
+
+func (x *Error) GetCause() *Cause {
+	if x != nil {
+		return x.Cause
+	}
+	return nil
+}
+
+func (x *Error) GetErrors() []*Error {
+	if x != nil {
+		return x.Errors
+	}
+	return nil
+}
+
+// Frame is a resolved stack frame
+type Frame struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Function      string                 `protobuf:"bytes,1,opt,name=function,proto3" json:"function,omitempty"`
+	File          string                 `protobuf:"bytes,2,opt,name=file,proto3" json:"file,omitempty"`
+	Line          int32                  `protobuf:"varint,3,opt,name=line,proto3" json:"line,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Frame) Reset() {
+	*x = Frame{}
+	mi := &file_xerror_xerrorpb_xerror_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Frame) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Frame) ProtoMessage() {}
+
+func (x *Frame) ProtoReflect() protoreflect.Message {
+	mi := &file_xerror_xerrorpb_xerror_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Frame.ProtoReflect.Descriptor instead.
+func (*Frame) Descriptor() ([]byte, []int) {
+	return file_xerror_xerrorpb_xerror_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *Frame) GetFunction() string {
+	if x != nil {
+		return x.Function
+	}
+	return ""
+}
+
+func (x *Frame) GetFile() string {
+	if x != nil {
+		return x.File
+	}
+	return ""
+}
+
+func (x *Frame) GetLine() int32 {
+	if x != nil {
+		return x.Line
+	}
+	return 0
+}
+
+type FieldViolation struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Field         string                 `protobuf:"bytes,1,opt,name=field,proto3" json:"field,omitempty"`
+	Rule          string                 `protobuf:"bytes,2,opt,name=rule,proto3" json:"rule,omitempty"`
+	Message       string                 `protobuf:"bytes,3,opt,name=message,proto3" json:"message,omitempty"`
+	Value         *structpb.Value        `protobuf:"bytes,4,opt,name=value,proto3" json:"value,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *FieldViolation) Reset() {
+	*x = FieldViolation{}
+	mi := &file_xerror_xerrorpb_xerror_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *FieldViolation) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*FieldViolation) ProtoMessage() {}
+
+func (x *FieldViolation) ProtoReflect() protoreflect.Message {
+	mi := &file_xerror_xerrorpb_xerror_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use FieldViolation.ProtoReflect.Descriptor instead.
+func (*FieldViolation) Descriptor() ([]byte, []int) {
+	return file_xerror_xerrorpb_xerror_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *FieldViolation) GetField() string {
+	if x != nil {
+		return x.Field
+	}
+	return ""
+}
+
+func (x *FieldViolation) GetRule() string {
+	if x != nil {
+		return x.Rule
+	}
+	return ""
+}
+
+func (x *FieldViolation) GetMessage() string {
+	if x != nil {
+		return x.Message
+	}
+	return ""
+}
+
+func (x *FieldViolation) GetValue() *structpb.Value {
+	if x != nil {
+		return x.Value
+	}
+	return nil
+}
+
+// Hop is a service the error went through
+type Hop struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	AppName       string                 `protobuf:"bytes,1,opt,name=app_name,json=appName,proto3" json:"app_name,omitempty"`
+	Caller        string                 `protobuf:"bytes,2,opt,name=caller,proto3" json:"caller,omitempty"`
+	Timestamp     *timestamppb.Timestamp `protobuf:"bytes,3,opt,name=timestamp,proto3" json:"timestamp,omitempty"`
+	TraceId       string                 `protobuf:"bytes,4,opt,name=trace_id,json=traceId,proto3" json:"trace_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Hop) Reset() {
+	*x = Hop{}
+	mi := &file_xerror_xerrorpb_xerror_proto_msgTypes[3]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Hop) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Hop) ProtoMessage() {}
+
+func (x *Hop) ProtoReflect() protoreflect.Message {
+	mi := &file_xerror_xerrorpb_xerror_proto_msgTypes[3]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Hop.ProtoReflect.Descriptor instead.
+func (*Hop) Descriptor() ([]byte, []int) {
+	return file_xerror_xerrorpb_xerror_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *Hop) GetAppName() string {
+	if x != nil {
+		return x.AppName
+	}
+	return ""
+}
+
+func (x *Hop) GetCaller() string {
+	if x != nil {
+		return x.Caller
+	}
+	return ""
+}
+
+func (x *Hop) GetTimestamp() *timestamppb.Timestamp {
+	if x != nil {
+		return x.Timestamp
+	}
+	return nil
+}
+
+func (x *Hop) GetTraceId() string {
+	if x != nil {
+		return x.TraceId
+	}
+	return ""
+}
+
+// Cause is either an xerror or a plain error with its message and own cause
+type Cause struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Error         *Error                 `protobuf:"bytes,1,opt,name=error,proto3" json:"error,omitempty"`
+	Message       string                 `protobuf:"bytes,2,opt,name=message,proto3" json:"message,omitempty"`
+	Cause         *Cause                 `protobuf:"bytes,3,opt,name=cause,proto3" json:"cause,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Cause) Reset() {
+	*x = Cause{}
+	mi := &file_xerror_xerrorpb_xerror_proto_msgTypes[4]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Cause) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Cause) ProtoMessage() {}
+
+func (x *Cause) ProtoReflect() protoreflect.Message {
+	mi := &file_xerror_xerrorpb_xerror_proto_msgTypes[4]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Cause.ProtoReflect.Descriptor instead.
+func (*Cause) Descriptor() ([]byte, []int) {
+	return file_xerror_xerrorpb_xerror_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *Cause) GetError() *Error {
+	if x != nil {
+		return x.Error
+	}
+	return nil
+}
+
+func (x *Cause) GetMessage() string {
+	if x != nil {
+		return x.Message
+	}
+	return ""
+}
+
+func (x *Cause) GetCause() *Cause {
+	if x != nil {
+		return x.Cause
+	}
+	return nil
+}
+
+var File_xerror_xerrorpb_xerror_proto protoreflect.FileDescriptor
+
+const file_xerror_xerrorpb_xerror_proto_rawDesc = "" +
+	"\n" +
+	"\x1cxerror/xerrorpb/xerror.proto\x12\rxgo.xerror.v1\x1a\x1cgoogle/protobuf/struct.proto\x1a\x1fgoogle/protobuf/timestamp.proto\"\xe2\x03\n" +
+	"\x05Error\x12\x12\n" +
+	"\x04code\x18\x01 \x01(\tR\x04code\x12\x18\n" +
+	"\amessage\x18\x02 \x01(\tR\amessage\x12\x16\n" +
+	"\x06caller\x18\x03 \x01(\tR\x06caller\x12\x16\n" +
+	"\x06detail\x18\x04 \x01(\tR\x06detail\x12\x19\n" +
+	"\bapp_name\x18\x05 \x01(\tR\aappName\x12+\n" +
+	"\x04info\x18\x06 \x01(\v2\x17.google.protobuf.StructR\x04info\x128\n" +
+	"\ttimestamp\x18\a \x01(\v2\x1a.google.protobuf.TimestampR\ttimestamp\x12,\n" +
+	"\x06frames\x18\b \x03(\v2\x14.xgo.xerror.v1.FrameR\x06frames\x12=\n" +
+	"\n" +
+	"violations\x18\t \x03(\v2\x1d.xgo.xerror.v1.FieldViolationR\n" +
+	"violations\x122\n" +
+	"\n" +
+	"provenance\x18\n" +
+	" \x03(\v2\x12.xgo.xerror.v1.HopR\n" +
+	"provenance\x12*\n" +
+	"\x05cause\x18\v \x01(\v2\x14.xgo.xerror.v1.CauseR\x05cause\x12,\n" +
+	"\x06errors\x18\f \x03(\v2\x14.xgo.xerror.v1.ErrorR\x06errors\"K\n" +
+	"\x05Frame\x12\x1a\n" +
+	"\bfunction\x18\x01 \x01(\tR\bfunction\x12\x12\n" +
+	"\x04file\x18\x02 \x01(\tR\x04file\x12\x12\n" +
+	"\x04line\x18\x03 \x01(\x05R\x04line\"\x82\x01\n" +
+	"\x0eFieldViolation\x12\x14\n" +
+	"\x05field\x18\x01 \x01(\tR\x05field\x12\x12\n" +
+	"\x04rule\x18\x02 \x01(\tR\x04rule\x12\x18\n" +
+	"\amessage\x18\x03 \x01(\tR\amessage\x12,\n" +
+	"\x05value\x18\x04 \x01(\v2\x16.google.protobuf.ValueR\x05value\"\x8d\x01\n" +
+	"\x03Hop\x12\x19\n" +
+	"\bapp_name\x18\x01 \x01(\tR\aappName\x12\x16\n" +
+	"\x06caller\x18\x02 \x01(\tR\x06caller\x128\n" +
+	"\ttimestamp\x18\x03 \x01(\v2\x1a.google.protobuf.TimestampR\ttimestamp\x12\x19\n" +
+	"\btrace_id\x18\x04 \x01(\tR\atraceId\"y\n" +
+	"\x05Cause\x12*\n" +
+	"\x05error\x18\x01 \x01(\v2\x14.xgo.xerror.v1.ErrorR\x05error\x12\x18\n" +
+	"\amessage\x18\x02 \x01(\tR\amessage\x12*\n" +
+	"\x05cause\x18\x03 \x01(\v2\x14.xgo.xerror.v1.CauseR\x05causeB,Z*github.com/kurzgesagtz/xgo/xerror/xerrorpbb\x06proto3"
+
+var (
+	file_xerror_xerrorpb_xerror_proto_rawDescOnce sync.Once
+	file_xerror_xerrorpb_xerror_proto_rawDescData []byte
+)
+
+func file_xerror_xerrorpb_xerror_proto_rawDescGZIP() []byte {
+	file_xerror_xerrorpb_xerror_proto_rawDescOnce.Do(func() {
+		file_xerror_xerrorpb_xerror_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_xerror_xerrorpb_xerror_proto_rawDesc), len(file_xerror_xerrorpb_xerror_proto_rawDesc)))
+	})
+	return file_xerror_xerrorpb_xerror_proto_rawDescData
+}
+
+var file_xerror_xerrorpb_xerror_proto_msgTypes = make([]protoimpl.MessageInfo, 5)
+var file_xerror_xerrorpb_xerror_proto_goTypes = []any{
+	(*Error)(nil),                 // 0: xgo.xerror.v1.Error
+	(*Frame)(nil),                 // 1: xgo.xerror.v1.Frame
+	(*FieldViolation)(nil),        // 2: xgo.xerror.v1.FieldViolation
+	(*Hop)(nil),                   // 3: xgo.xerror.v1.Hop
+	(*Cause)(nil),                 // 4: xgo.xerror.v1.Cause
+	(*structpb.Struct)(nil),       // 5: google.protobuf.Struct
+	(*timestamppb.Timestamp)(nil), // 6: google.protobuf.Timestamp
+	(*structpb.Value)(nil),        // 7: google.protobuf.Value
+}
+var file_xerror_xerrorpb_xerror_proto_depIdxs = []int32{
+	5,  // 0: xgo.xerror.v1.Error.info:type_name -> google.protobuf.Struct
+	6,  // 1: xgo.xerror.v1.Error.timestamp:type_name -> google.protobuf.Timestamp
+	1,  // 2: xgo.xerror.v1.Error.frames:type_name -> xgo.xerror.v1.Frame
+	2,  // 3: xgo.xerror.v1.Error.violations:type_name -> xgo.xerror.v1.FieldViolation
+	3,  // 4: xgo.xerror.v1.Error.provenance:type_name -> xgo.xerror.v1.Hop
+	4,  // 5: xgo.xerror.v1.Error.cause:type_name -> xgo.xerror.v1.Cause
+	0,  // 6: xgo.xerror.v1.Error.errors:type_name -> xgo.xerror.v1.Error
+	7,  // 7: xgo.xerror.v1.FieldViolation.value:type_name -> google.protobuf.Value
+	6,  // 8: xgo.xerror.v1.Hop.timestamp:type_name -> google.protobuf.Timestamp
+	0,  // 9: xgo.xerror.v1.Cause.error:type_name -> xgo.xerror.v1.Error
+	4,  // 10: xgo.xerror.v1.Cause.cause:type_name -> xgo.xerror.v1.Cause
+	11, // [11:11] is the sub-list for method output_type
+	11, // [11:11] is the sub-list for method input_type
+	11, // [11:11] is the sub-list for extension type_name
+	11, // [11:11] is the sub-list for extension extendee
+	0,  // [0:11] is the sub-list for field type_name
+}
+
+func init() { file_xerror_xerrorpb_xerror_proto_init() }
+func file_xerror_xerrorpb_xerror_proto_init() {
+	if File_xerror_xerrorpb_xerror_proto != nil {
+		return
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_xerror_xerrorpb_xerror_proto_rawDesc), len(file_xerror_xerrorpb_xerror_proto_rawDesc)),
+			NumEnums:      0,
+			NumMessages:   5,
+			NumExtensions: 0,
+			NumServices:   0,
+		},
+		GoTypes:           file_xerror_xerrorpb_xerror_proto_goTypes,
+		DependencyIndexes: file_xerror_xerrorpb_xerror_proto_depIdxs,
+		MessageInfos:      file_xerror_xerrorpb_xerror_proto_msgTypes,
+	}.Build()
+	File_xerror_xerrorpb_xerror_proto = out.File
+	file_xerror_xerrorpb_xerror_proto_goTypes = nil
+	file_xerror_xerrorpb_xerror_proto_depIdxs = nil
+}
@@ -0,0 +1,80 @@
+package xlog
+
+import (
+	"context"
+	"fmt"
+	"github.com/gin-gonic/gin"
+)
+
+type contextFieldsKey struct{}
+
+// contextField is a field attached to a context with WithFields
+type contextField struct {
+	key   string
+	value any
+}
+
+// WithFields returns a context carrying the key-value pairs kv in addition to the fields of ctx, a key set again
+// replaces the previous value. Every event given the context with LogEvent.Context or created by FromContext
+// includes them, a *gin.Context gets them in its request context and is returned as is, a nil ctx is
+// treated as context.Background()
+func WithFields(ctx context.Context, kv ...any) context.Context {
+	if gCtx, ok := ctx.(*gin.Context); ok && gCtx.Request != nil {
+		gCtx.Request = gCtx.Request.WithContext(WithFields(gCtx.Request.Context(), kv...))
+		return gCtx
+	}
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	parent := contextFields(ctx)
+	fields := make([]contextField, len(parent), len(parent)+(len(kv)+1)/2)
+	copy(fields, parent)
+	for i := 0; i < len(kv); i += 2 {
+		key, ok := kv[i].(string)
+		if !ok {
+			key = fmt.Sprint(kv[i])
+		}
+		var value any
+		if i+1 < len(kv) {
+			value = kv[i+1]
+		}
+		fields = setContextField(fields, key, value)
+	}
+	return context.WithValue(ctx, contextFieldsKey{}, fields)
+}
+
+func setContextField(fields []contextField, key string, value any) []contextField {
+	for i := range fields {
+		if fields[i].key == key {
+			fields[i].value = value
+			return fields
+		}
+	}
+	return append(fields, contextField{key: key, value: value})
+}
+
+func contextFields(ctx context.Context) []contextField {
+	if ctx == nil {
+		return nil
+	}
+	fields, _ := ctx.Value(contextFieldsKey{}).([]contextField)
+	return fields
+}
+
+// Fields returns a copy of the fields attached to ctx with WithFields
+func Fields(ctx context.Context) map[string]any {
+	if gCtx, ok := ctx.(*gin.Context); ok && gCtx.Request != nil {
+		ctx = gCtx.Request.Context()
+	}
+	fields := contextFields(ctx)
+	out := make(map[string]any, len(fields))
+	for _, f := range fields {
+		out[f.key] = f.value
+	}
+	return out
+}
+
+// FromContext returns a logger whose events are given ctx, they include its fields, trace ids and gin request data
+func FromContext(ctx context.Context) *Logger {
+	return &Logger{ctx: ctx}
+}
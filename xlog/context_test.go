@@ -0,0 +1,113 @@
+package xlog
+
+import (
+	"context"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap/zapcore"
+)
+
+func TestWithFields(t *testing.T) {
+	ctx := WithFields(context.Background(), "user_id", 42, "tenant_id", "acme")
+	child := WithFields(ctx, "user_id", 43, "request_id")
+
+	parent := Fields(ctx)
+	if parent["user_id"] != 42 || parent["tenant_id"] != "acme" || len(parent) != 2 {
+		t.Errorf("Expected parent fields to be unchanged, got %v", parent)
+	}
+
+	fields := Fields(child)
+	if fields["user_id"] != 43 || fields["tenant_id"] != "acme" {
+		t.Errorf("Expected accumulated fields, got %v", fields)
+	}
+	if v, ok := fields["request_id"]; !ok || v != nil {
+		t.Errorf("Expected key without value to be nil, got %v", v)
+	}
+
+	if fields := Fields(context.Background()); len(fields) != 0 {
+		t.Errorf("Expected no fields, got %v", fields)
+	}
+}
+
+func TestLogEvent_ContextFields(t *testing.T) {
+	ctx := WithFields(context.Background(), "user_id", 42, "tenant_id", "acme")
+
+	event := newLogEvent("", zapcore.InfoLevel).Context(ctx)
+	if event.raw.fields["user_id"] != 42 || event.raw.fields["tenant_id"] != "acme" {
+		t.Errorf("Expected context fields, got %v", event.raw.fields)
+	}
+
+	keys := make(map[string]int)
+	for _, f := range event.fields {
+		keys[f.Key]++
+	}
+	if keys["user_id"] != 1 || keys["tenant_id"] != 1 {
+		t.Errorf("Expected each context field once, got %v", keys)
+	}
+}
+
+func TestWithFields_Gin(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request = httptest.NewRequest(http.MethodGet, "/invoices", nil)
+
+	if got := WithFields(c, "user_id", 42); got != c {
+		t.Error("Expected the gin context to be returned")
+	}
+
+	event := newLogEvent("", zapcore.InfoLevel).Context(c)
+	if event.raw.fields["user_id"] != 42 || event.raw.fields["path"] != "/invoices" {
+		t.Errorf("Expected gin and context fields, got %v", event.raw.fields)
+	}
+	if Fields(c)["user_id"] != 42 {
+		t.Errorf("Expected fields of the gin context, got %v", Fields(c))
+	}
+}
+
+func TestFromContext(t *testing.T) {
+	restoreLevels(t)
+	SetLevel(zapcore.DebugLevel)
+	ctx := WithFields(context.Background(), "tenant_id", "acme")
+
+	logger := FromContext(ctx).Named("billing")
+	if logger.Name() != "billing" {
+		t.Errorf("Expected billing, got %s", logger.Name())
+	}
+	event := logger.Info()
+	if event.raw.fields["tenant_id"] != "acme" || event.name != "billing" {
+		t.Errorf("Expected context fields on named event, got %v", event.raw.fields)
+	}
+	event.Msg("context logger")
+
+	SetNamedLevel("billing", zapcore.ErrorLevel)
+	if ev := logger.Info(); ev != nil {
+		t.Error("Expected disabled event to be nil")
+	}
+}
+
+func TestLogEvent_NilContext(t *testing.T) {
+	event := newLogEvent("", zapcore.ErrorLevel)
+	if got := event.Context(nil); got != event {
+		t.Fatal("Expected Context(nil) to return the same LogEvent instance")
+	}
+	FromContext(nil).Error().Msg("nil context")
+	if fields := Fields(WithFields(nil, "user_id", 42)); fields["user_id"] != 42 {
+		t.Errorf("Expected fields on a nil context, got %v", fields)
+	}
+
+	read := captureLogs(t)
+	Error().Context(nil).Msg("logged with nil context")
+	if err := NewSlogHandler("").Handle(nil, slog.NewRecord(time.Now(), slog.LevelError, "slog with nil context", 0)); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	entries := read()
+	if len(entries) != 2 || entries[0]["msg"] != "logged with nil context" || entries[1]["msg"] != "slog with nil context" {
+		t.Errorf("Expected events with a nil context to be logged, got %v", entries)
+	}
+}
@@ -9,6 +9,8 @@ import (
 	"go.opentelemetry.io/otel/trace"
 	"go.uber.org/zap"
 	"go.uber.org/zap/zapcore"
+	"runtime"
+	"time"
 )
 
 type rawEvent struct {
@@ -16,7 +18,10 @@ type rawEvent struct {
 	data   map[string]any
 }
 
+// LogEvent is a log being built, an event below the level of its logger is nil and its methods do nothing
 type LogEvent struct {
+	state      *loggerState
+	name       string
 	level      zapcore.Level
 	appName    string
 	err        error
@@ -25,21 +30,38 @@ type LogEvent struct {
 	raw        rawEvent
 	pretty     bool
 	callerSkip int
+	// frame is the caller recorded by the slog and gorm adapters, it replaces the caller of Msg when it is set
+	frame runtime.Frame
+	// timestamp is the time of the slog record, it replaces the time of Msg when hasTimestamp is set and a zero
+	// timestamp omits the time
+	timestamp    time.Time
+	hasTimestamp bool
 }
 
-func newLogEvent(level zapcore.Level) *LogEvent {
+func newLogEvent(name string, level zapcore.Level) *LogEvent {
+	if !Enabled(name, level) {
+		return nil
+	}
+	return buildLogEvent(name, level)
+}
+
+// buildLogEvent creates an event without checking the level, for the messages which must be logged whatever the level
+func buildLogEvent(name string, level zapcore.Level) *LogEvent {
+	st := state.Load()
 	ev := &LogEvent{
+		state:   st,
+		name:    name,
 		level:   level,
 		data:    make([]zapcore.Field, 0),
 		fields:  make([]zapcore.Field, 0),
-		appName: appName,
+		appName: st.appName,
 		pretty:  false,
 		raw: rawEvent{
 			fields: make(map[string]any),
 			data:   make(map[string]any),
 		},
 	}
-	ev.fields = append(ev.fields, zap.Any("app_name", appName))
+	ev.fields = append(ev.fields, zap.Any("app_name", st.appName))
 	return ev
 }
 
@@ -49,6 +71,9 @@ func (l *LogEvent) addField(key string, value any) {
 }
 
 func (l *LogEvent) Err(err error) *LogEvent {
+	if l == nil {
+		return nil
+	}
 	if err == nil {
 		return l
 	}
@@ -64,10 +89,36 @@ func (l *LogEvent) Err(err error) *LogEvent {
 			l.addField("error_app_name", xErr.AppName)
 		}
 	}
+	if provenance := xerror.GetProvenance(err); len(provenance) > 0 {
+		l.addField("error_provenance", xerror.FormatProvenance(provenance))
+	}
+	if causes := errorCauses(err); len(causes) > 0 {
+		l.addField("error_causes", causes)
+	}
 	return l
 }
 
+// errorCauses flattens the cause chain of err, the first item is the direct cause
+func errorCauses(err error) []string {
+	causes := make([]string, 0)
+	for cause := errors.Unwrap(err); cause != nil; cause = errors.Unwrap(cause) {
+		if xErr, ok := cause.(*xerror.Error); ok {
+			causes = append(causes, fmt.Sprintf("%s: %s", xErr.Code, xErr.Message))
+		} else {
+			causes = append(causes, cause.Error())
+		}
+	}
+	return causes
+}
+
+// Context adds the request data of a *gin.Context, the trace ids and the fields attached with WithFields
 func (l *LogEvent) Context(ctx context.Context) *LogEvent {
+	if l == nil {
+		return nil
+	}
+	if ctx == nil {
+		return l
+	}
 	if gCtx, ok := ctx.(*gin.Context); ok {
 		l.addField("ip_address", gCtx.ClientIP())
 		l.addField("user_agent", gCtx.Request.UserAgent())
@@ -81,28 +132,47 @@ func (l *LogEvent) Context(ctx context.Context) *LogEvent {
 		l.addField("trace_id", span.TraceID().String())
 		l.addField("trace_sample", span.IsSampled())
 	}
+	for _, f := range contextFields(ctx) {
+		l.addField(f.key, f.value)
+	}
 	return l
 }
 
 func (l *LogEvent) AddCallerSkip(n int) *LogEvent {
+	if l == nil {
+		return nil
+	}
 	l.callerSkip += n
 	return l
 }
 
 func (l *LogEvent) Field(key string, val any) *LogEvent {
+	if l == nil {
+		return nil
+	}
 	l.data = append(l.data, zap.Any(key, val))
 	l.raw.data[key] = val
 	return l
 }
 
 func (l *LogEvent) Pretty() *LogEvent {
+	if l == nil {
+		return nil
+	}
 	l.pretty = true
 	return l
 }
 
 func (l *LogEvent) Msg(msg string) {
+	if l == nil {
+		return
+	}
+	mode, logger := l.state.mode, l.state.logger
+	if l.name != "" {
+		logger = logger.Named(l.name)
+	}
 	if mode == pretty || (mode == development && l.pretty) {
-		logger.WithOptions(zap.AddCallerSkip(l.callerSkip)).Log(l.level, msg)
+		l.write(logger, msg)
 
 		raw := make(map[string]interface{})
 		if len(l.raw.fields) > 0 {
@@ -129,7 +199,23 @@ func (l *LogEvent) Msg(msg string) {
 			}
 		}
 	} else {
-		logger.WithOptions(zap.AddCallerSkip(l.callerSkip)).
-			Log(l.level, msg, append(l.fields, zap.Any("data", l.data))...)
+		l.write(logger, msg, append(l.fields, zap.Any("data", l.data))...)
+	}
+}
+
+// write logs with the caller of Msg, or the caller and the time recorded by the slog and gorm adapters
+func (l *LogEvent) write(logger *zap.Logger, msg string, fields ...zapcore.Field) {
+	if l.frame.File == "" && !l.hasTimestamp {
+		logger.WithOptions(zap.AddCallerSkip(l.callerSkip+1)).Log(l.level, msg, fields...)
+		return
+	}
+	if ce := logger.WithOptions(zap.AddCallerSkip(l.callerSkip+1)).Check(l.level, msg); ce != nil {
+		if l.frame.File != "" {
+			ce.Caller = zapcore.NewEntryCaller(l.frame.PC, l.frame.File, l.frame.Line, true)
+		}
+		if l.hasTimestamp {
+			ce.Time = l.timestamp
+		}
+		ce.Write(fields...)
 	}
 }
@@ -12,7 +12,9 @@ import (
 )
 
 func TestNewLogEvent(t *testing.T) {
-	// Test creating a new log event with different levels
+	// Test creating a new log event with different levels, every level is enabled
+	restoreLevels(t)
+	SetLevel(zapcore.DebugLevel)
 	levels := []zapcore.Level{
 		zapcore.DebugLevel,
 		zapcore.InfoLevel,
@@ -23,7 +25,7 @@ func TestNewLogEvent(t *testing.T) {
 	}
 
 	for _, level := range levels {
-		event := newLogEvent(level)
+		event := newLogEvent("", level)
 
 		if event == nil {
 			t.Errorf("Expected non-nil LogEvent for level %v", level)
@@ -34,8 +36,8 @@ func TestNewLogEvent(t *testing.T) {
 			t.Errorf("Expected level %v, got %v", level, event.level)
 		}
 
-		if event.appName != appName {
-			t.Errorf("Expected appName %s, got %s", appName, event.appName)
+		if event.appName != state.Load().appName {
+			t.Errorf("Expected appName %s, got %s", state.Load().appName, event.appName)
 		}
 
 		if event.pretty {
@@ -63,7 +65,7 @@ func TestNewLogEvent(t *testing.T) {
 
 func TestLogEvent_Err(t *testing.T) {
 	// Test with nil error
-	event := newLogEvent(zapcore.InfoLevel)
+	event := newLogEvent("", zapcore.InfoLevel)
 	result := event.Err(nil)
 
 	if result != event {
@@ -76,7 +78,7 @@ func TestLogEvent_Err(t *testing.T) {
 
 	// Test with standard error
 	stdErr := errors.New("standard error")
-	event = newLogEvent(zapcore.InfoLevel)
+	event = newLogEvent("", zapcore.InfoLevel)
 	result = event.Err(stdErr)
 
 	if result != event {
@@ -102,7 +104,7 @@ func TestLogEvent_Err(t *testing.T) {
 
 	// Test with xerror.Error
 	xErr := xerror.NewError("TEST_CODE", xerror.WithMessage("test message"))
-	event = newLogEvent(zapcore.InfoLevel)
+	event = newLogEvent("", zapcore.InfoLevel)
 	result = event.Err(xErr)
 
 	if result != event {
@@ -130,7 +132,7 @@ func TestLogEvent_Err(t *testing.T) {
 func TestLogEvent_Context(t *testing.T) {
 	// Test with standard context
 	ctx := context.Background()
-	event := newLogEvent(zapcore.InfoLevel)
+	event := newLogEvent("", zapcore.InfoLevel)
 	result := event.Context(ctx)
 
 	if result != event {
@@ -148,7 +150,7 @@ func TestLogEvent_Context(t *testing.T) {
 	}
 	c.Request.Header.Set("User-Agent", "test-agent")
 
-	event = newLogEvent(zapcore.InfoLevel)
+	event = newLogEvent("", zapcore.InfoLevel)
 	result = event.Context(c)
 
 	if result != event {
@@ -177,7 +179,7 @@ func TestLogEvent_Context(t *testing.T) {
 }
 
 func TestLogEvent_AddCallerSkip(t *testing.T) {
-	event := newLogEvent(zapcore.InfoLevel)
+	event := newLogEvent("", zapcore.InfoLevel)
 	result := event.AddCallerSkip(2)
 
 	if result != event {
@@ -197,7 +199,7 @@ func TestLogEvent_AddCallerSkip(t *testing.T) {
 }
 
 func TestLogEvent_Field(t *testing.T) {
-	event := newLogEvent(zapcore.InfoLevel)
+	event := newLogEvent("", zapcore.InfoLevel)
 	result := event.Field("test_key", "test_value")
 
 	if result != event {
@@ -224,7 +226,7 @@ func TestLogEvent_Field(t *testing.T) {
 }
 
 func TestLogEvent_Pretty(t *testing.T) {
-	event := newLogEvent(zapcore.InfoLevel)
+	event := newLogEvent("", zapcore.InfoLevel)
 	result := event.Pretty()
 
 	if result != event {
@@ -267,16 +269,19 @@ func TestLogEvent_Msg(t *testing.T) {
 		},
 	}
 
-	// Save original mode and restore it after the test
-	originalMode := mode
-	defer func() { mode = originalMode }()
+	// Save original logger and restore it after the test
+	original := state.Load()
+	defer state.Store(original)
+	restoreLevels(t)
 
 	for _, tc := range tests {
 		t.Run(tc.name, func(t *testing.T) {
 			// Set mode for this test
-			mode = tc.mode
+			if err := Configure(Config{Mode: tc.mode}); err != nil {
+				t.Fatalf("Expected no error, got %v", err)
+			}
 
-			event := newLogEvent(zapcore.InfoLevel)
+			event := newLogEvent("", zapcore.InfoLevel)
 			if tc.pretty {
 				event.Pretty()
 			}
@@ -289,3 +294,51 @@ func TestLogEvent_Msg(t *testing.T) {
 		})
 	}
 }
+
+func TestLogEvent_ErrWithCause(t *testing.T) {
+	root := errors.New("connection refused")
+	xErr := xerror.Wrap(xerror.NewError(xerror.ErrCodeServiceInternalError, xerror.WithCause(root)), xerror.ErrCodeInternalError)
+
+	event := newLogEvent("", zapcore.InfoLevel)
+	event.Err(xErr)
+
+	causes, ok := event.raw.fields["error_causes"].([]string)
+	if !ok {
+		t.Fatalf("Expected error_causes field to be set, got %v", event.raw.fields["error_causes"])
+	}
+
+	expected := []string{"SERVICE_INTERNAL_ERROR: Service internal error", "connection refused"}
+	if len(causes) != len(expected) {
+		t.Fatalf("Expected %d causes, got %d", len(expected), len(causes))
+	}
+	for i := range expected {
+		if causes[i] != expected[i] {
+			t.Errorf("Expected cause %d to be %q, got %q", i, expected[i], causes[i])
+		}
+	}
+
+	// Errors without cause don't add the field
+	event = newLogEvent("", zapcore.InfoLevel)
+	event.Err(errors.New("standard error"))
+	if _, ok := event.raw.fields["error_causes"]; ok {
+		t.Error("Expected error_causes field not to be set")
+	}
+}
+
+func TestLogEvent_ErrWithProvenance(t *testing.T) {
+	remote := xerror.NewError(xerror.ErrCodeNotFound)
+	remote.Provenance = []xerror.Hop{{AppName: "user-service"}, {AppName: "order-service"}, {AppName: "gateway"}}
+
+	event := newLogEvent("", zapcore.InfoLevel)
+	event.Err(xerror.Wrap(remote, xerror.ErrCodeInternalError))
+
+	if v := event.raw.fields["error_provenance"]; v != "user-service -> order-service -> gateway" {
+		t.Errorf("Expected error_provenance field, got %v", v)
+	}
+
+	event = newLogEvent("", zapcore.InfoLevel)
+	event.Err(xerror.NewError(xerror.ErrCodeNotFound))
+	if _, ok := event.raw.fields["error_provenance"]; ok {
+		t.Error("Expected error_provenance field not to be set")
+	}
+}
@@ -0,0 +1,148 @@
+package xlog
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"runtime"
+	"strings"
+	"time"
+
+	"github.com/kurzgesagtz/xgo/xerror"
+	"go.uber.org/zap/zapcore"
+	gormlogger "gorm.io/gorm/logger"
+)
+
+// GormConfig configures GormLogger, zero values fall back to the defaults
+type GormConfig struct {
+	// Name is the logger name of the queries, default is "gorm", see SetNamedLevel
+	Name string
+	// LogLevel filters the logs of gorm before xlog, default is gormlogger.Info which logs every query at debug level
+	LogLevel gormlogger.LogLevel
+	// SlowThreshold logs the queries slower than it as warnings, default is 200ms and a negative value disables it
+	SlowThreshold time.Duration
+	// IgnoreRecordNotFoundError doesn't log gorm.ErrRecordNotFound as an error
+	IgnoreRecordNotFoundError bool
+	// ShowParams logs the bound parameters of the queries, they are replaced with xerror.RedactedValue otherwise
+	ShowParams bool
+}
+
+// GormLogger is a gorm logger.Interface writing to xlog, queries are logged with their duration, the rows affected,
+// the trace ids and the fields of the context. Successful queries are debug logs, slow queries warnings
+// and failed queries errors
+type GormLogger struct {
+	cfg GormConfig
+}
+
+// NewGormLogger returns a gorm logger, set it with gorm.Config{Logger: xlog.NewGormLogger(xlog.GormConfig{})}
+func NewGormLogger(cfg GormConfig) *GormLogger {
+	if cfg.Name == "" {
+		cfg.Name = "gorm"
+	}
+	if cfg.LogLevel == 0 {
+		cfg.LogLevel = gormlogger.Info
+	}
+	if cfg.SlowThreshold == 0 {
+		cfg.SlowThreshold = 200 * time.Millisecond
+	}
+	return &GormLogger{cfg: cfg}
+}
+
+// LogMode returns a copy of l with the gorm level, db.Debug() uses it to log every query
+func (l *GormLogger) LogMode(level gormlogger.LogLevel) gormlogger.Interface {
+	cp := *l
+	cp.cfg.LogLevel = level
+	return &cp
+}
+
+func (l *GormLogger) Info(ctx context.Context, msg string, data ...any) {
+	if l.cfg.LogLevel >= gormlogger.Info {
+		l.log(ctx, zapcore.InfoLevel, msg, data)
+	}
+}
+
+func (l *GormLogger) Warn(ctx context.Context, msg string, data ...any) {
+	if l.cfg.LogLevel >= gormlogger.Warn {
+		l.log(ctx, zapcore.WarnLevel, msg, data)
+	}
+}
+
+func (l *GormLogger) Error(ctx context.Context, msg string, data ...any) {
+	if l.cfg.LogLevel >= gormlogger.Error {
+		l.log(ctx, zapcore.ErrorLevel, msg, data)
+	}
+}
+
+func (l *GormLogger) log(ctx context.Context, level zapcore.Level, msg string, data []any) {
+	ev := newLogEvent(l.cfg.Name, level)
+	if ev == nil {
+		return
+	}
+	ev.frame = gormCaller(1)
+	ev.Context(ctx).Msg(fmt.Sprintf(msg, data...))
+}
+
+// Trace logs a query, fc is only called when the query is logged
+func (l *GormLogger) Trace(ctx context.Context, begin time.Time, fc func() (sql string, rowsAffected int64), err error) {
+	if l.cfg.LogLevel <= gormlogger.Silent {
+		return
+	}
+	elapsed := time.Since(begin)
+	var ev *LogEvent
+	msg := "sql query"
+	switch {
+	case err != nil && l.cfg.LogLevel >= gormlogger.Error &&
+		(!l.cfg.IgnoreRecordNotFoundError || !errors.Is(err, gormlogger.ErrRecordNotFound)):
+		ev = newLogEvent(l.cfg.Name, zapcore.ErrorLevel).Err(err)
+		msg = "sql query failed"
+	case l.cfg.SlowThreshold > 0 && elapsed > l.cfg.SlowThreshold && l.cfg.LogLevel >= gormlogger.Warn:
+		ev = newLogEvent(l.cfg.Name, zapcore.WarnLevel).Field("slow_threshold_ms", durationMs(l.cfg.SlowThreshold))
+		msg = "slow sql query"
+	case l.cfg.LogLevel >= gormlogger.Info:
+		ev = newLogEvent(l.cfg.Name, zapcore.DebugLevel)
+	}
+	if ev == nil {
+		return
+	}
+	ev.frame = gormCaller(0)
+	sql, rows := fc()
+	ev = ev.Context(ctx).Field("sql", sql).Field("duration_ms", durationMs(elapsed))
+	if rows >= 0 {
+		ev.Field("rows_affected", rows)
+	}
+	ev.Msg(msg)
+}
+
+// ParamsFilter replaces the bound parameters with xerror.RedactedValue unless ShowParams is set, gorm calls it
+// before the parameters are rendered in the query
+func (l *GormLogger) ParamsFilter(_ context.Context, sql string, params ...any) (string, []any) {
+	if l.cfg.ShowParams {
+		return sql, params
+	}
+	redacted := make([]any, len(params))
+	for i := range redacted {
+		redacted[i] = xerror.RedactedValue
+	}
+	return sql, redacted
+}
+
+func durationMs(d time.Duration) float64 {
+	return float64(d.Nanoseconds()) / 1e6
+}
+
+// gormCaller returns the first frame outside of gorm, it is the code which ran the query.
+// skip is the number of frames to skip above the caller of gormCaller
+func gormCaller(skip int) runtime.Frame {
+	var pcs [32]uintptr
+	n := runtime.Callers(3+skip, pcs[:])
+	frames := runtime.CallersFrames(pcs[:n])
+	for {
+		frame, more := frames.Next()
+		if !strings.HasPrefix(frame.Function, "gorm.io/") {
+			return frame
+		}
+		if !more {
+			return runtime.Frame{}
+		}
+	}
+}
@@ -0,0 +1,159 @@
+package xlog
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/kurzgesagtz/xgo/xerror"
+	"go.uber.org/zap/zapcore"
+	"gorm.io/gorm"
+	"gorm.io/gorm/callbacks"
+	"gorm.io/gorm/clause"
+	gormlogger "gorm.io/gorm/logger"
+	"gorm.io/gorm/schema"
+)
+
+// dryRunDialector builds the SQL of gorm statements without a database
+type dryRunDialector struct{}
+
+func (dryRunDialector) Name() string { return "dryrun" }
+
+func (dryRunDialector) Initialize(db *gorm.DB) error {
+	callbacks.RegisterDefaultCallbacks(db, &callbacks.Config{})
+	return nil
+}
+
+func (dryRunDialector) Migrator(*gorm.DB) gorm.Migrator { return nil }
+
+func (dryRunDialector) DataTypeOf(*schema.Field) string { return "" }
+
+func (dryRunDialector) DefaultValueOf(*schema.Field) clause.Expression { return nil }
+
+func (dryRunDialector) BindVarTo(writer clause.Writer, _ *gorm.Statement, _ any) {
+	_ = writer.WriteByte('?')
+}
+
+func (dryRunDialector) QuoteTo(writer clause.Writer, str string) {
+	_, _ = writer.WriteString("`" + str + "`")
+}
+
+func (dryRunDialector) Explain(sql string, vars ...any) string {
+	return gormlogger.ExplainSQL(sql, nil, `'`, vars...)
+}
+
+type gormUser struct {
+	ID    int
+	Email string
+}
+
+func TestGormLogger_Query(t *testing.T) {
+	read := captureLogs(t)
+
+	for _, showParams := range []bool{false, true} {
+		db, err := gorm.Open(dryRunDialector{}, &gorm.Config{
+			DryRun: true,
+			Logger: NewGormLogger(GormConfig{ShowParams: showParams}),
+		})
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		ctx := WithFields(context.Background(), "tenant_id", "acme")
+		var users []gormUser
+		db.WithContext(ctx).Where("email = ?", "jane@example.com").Find(&users)
+	}
+
+	entries := read()
+	if len(entries) != 2 {
+		t.Fatalf("Expected 2 entries, got %v", entries)
+	}
+	entry := entries[0]
+	if entry["msg"] != "sql query" || entry["level"] != "debug" || entry["logger"] != "gorm" || entry["tenant_id"] != "acme" {
+		t.Errorf("Unexpected entry %v", entry)
+	}
+	if caller, _ := entry["caller"].(string); !strings.HasPrefix(caller, "xlog/gorm_test.go:") {
+		t.Errorf("Expected caller of the query, got %v", entry["caller"])
+	}
+	data, _ := entry["data"].(map[string]any)
+	if sql, _ := data["sql"].(string); !strings.Contains(sql, "'"+xerror.RedactedValue+"'") || strings.Contains(sql, "jane@example.com") {
+		t.Errorf("Expected redacted parameters, got %v", data["sql"])
+	}
+	if _, ok := data["duration_ms"].(float64); !ok {
+		t.Errorf("Expected duration, got %v", data)
+	}
+
+	data, _ = entries[1]["data"].(map[string]any)
+	if sql, _ := data["sql"].(string); !strings.Contains(sql, "'jane@example.com'") {
+		t.Errorf("Expected parameters to be shown, got %v", data["sql"])
+	}
+}
+
+func TestGormLogger_Trace(t *testing.T) {
+	read := captureLogs(t)
+	fc := func() (string, int64) { return "UPDATE `users` SET `email`='[REDACTED]'", 3 }
+	ctx := context.Background()
+
+	l := NewGormLogger(GormConfig{SlowThreshold: 10 * time.Millisecond, IgnoreRecordNotFoundError: true})
+	l.Trace(ctx, time.Now().Add(-time.Second), fc, nil)
+	l.Trace(ctx, time.Now(), fc, errors.New("deadlock detected"))
+	l.Trace(ctx, time.Now(), fc, gormlogger.ErrRecordNotFound)
+	l.Trace(ctx, time.Now(), func() (string, int64) { return "SELECT 1", -1 }, nil)
+	l.LogMode(gormlogger.Silent).Trace(ctx, time.Now(), fc, errors.New("ignored"))
+	l.LogMode(gormlogger.Warn).Trace(ctx, time.Now(), fc, nil)
+	l.Warn(ctx, "unsupported %s", "feature")
+
+	entries := read()
+	expected := []struct {
+		msg   string
+		level string
+	}{
+		{"slow sql query", "warn"},
+		{"sql query failed", "error"},
+		{"sql query", "debug"},
+		{"sql query", "debug"},
+		{"unsupported feature", "warn"},
+	}
+	if len(entries) != len(expected) {
+		t.Fatalf("Expected %d entries, got %v", len(expected), entries)
+	}
+	for i, e := range expected {
+		if entries[i]["msg"] != e.msg || entries[i]["level"] != e.level {
+			t.Errorf("Expected %s %s, got %v", e.level, e.msg, entries[i])
+		}
+	}
+
+	data, _ := entries[0]["data"].(map[string]any)
+	if data["rows_affected"] != float64(3) || data["slow_threshold_ms"] != float64(10) {
+		t.Errorf("Unexpected slow query data %v", data)
+	}
+	if entries[1]["error"] != "deadlock detected" {
+		t.Errorf("Expected error, got %v", entries[1])
+	}
+	if data, _ := entries[3]["data"].(map[string]any); data["rows_affected"] != nil {
+		t.Errorf("Expected unknown rows to be omitted, got %v", data)
+	}
+}
+
+func TestGormLogger_Level(t *testing.T) {
+	restoreLevels(t)
+	SetLevel(zapcore.InfoLevel)
+
+	called := false
+	fc := func() (string, int64) {
+		called = true
+		return "SELECT 1", 1
+	}
+	l := NewGormLogger(GormConfig{})
+	l.Trace(context.Background(), time.Now(), fc, nil)
+	if called {
+		t.Error("Expected debug query not to be rendered at info level")
+	}
+
+	SetNamedLevel("gorm", zapcore.DebugLevel)
+	l.Trace(context.Background(), time.Now(), fc, nil)
+	if !called {
+		t.Error("Expected named level to enable queries")
+	}
+}
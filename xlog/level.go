@@ -0,0 +1,171 @@
+package xlog
+
+import (
+	"encoding/json"
+	"fmt"
+	"github.com/kurzgesagtz/xgo/xerror"
+	"go.uber.org/zap/zapcore"
+	"net/http"
+	"strings"
+	"sync"
+	"sync/atomic"
+)
+
+// level is the minimum level of loggers without an override, it is set by Configure and SetLevel
+var level atomic.Int32
+
+// namedLevels maps a logger name to its level override, it is replaced as a whole on change
+var namedLevels atomic.Pointer[map[string]zapcore.Level]
+
+// levelMu serializes the updates of namedLevels
+var levelMu sync.Mutex
+
+func init() {
+	namedLevels.Store(&map[string]zapcore.Level{})
+}
+
+// Level returns the minimum level of loggers without an override
+func Level() zapcore.Level {
+	return zapcore.Level(level.Load())
+}
+
+// SetLevel changes the minimum level of loggers without an override, events below it are not built
+func SetLevel(l zapcore.Level) {
+	level.Store(int32(l))
+}
+
+// SetNamedLevel overrides the level of the logger name and its children, "billing" applies to "billing.db" too
+func SetNamedLevel(name string, l zapcore.Level) {
+	updateNamedLevels(func(m map[string]zapcore.Level) {
+		m[name] = l
+	})
+}
+
+// ResetNamedLevel removes the override of the logger name, it falls back to its parent or the global level
+func ResetNamedLevel(name string) {
+	updateNamedLevels(func(m map[string]zapcore.Level) {
+		delete(m, name)
+	})
+}
+
+// NamedLevels returns a copy of the level overrides by logger name
+func NamedLevels() map[string]zapcore.Level {
+	current := *namedLevels.Load()
+	out := make(map[string]zapcore.Level, len(current))
+	for k, v := range current {
+		out[k] = v
+	}
+	return out
+}
+
+func updateNamedLevels(fn func(map[string]zapcore.Level)) {
+	levelMu.Lock()
+	defer levelMu.Unlock()
+	m := NamedLevels()
+	fn(m)
+	namedLevels.Store(&m)
+}
+
+// Enabled reports whether an event of lvl is logged by the logger name, an empty name is the root logger.
+// Panic and fatal events are always enabled since they stop the program
+func Enabled(name string, lvl zapcore.Level) bool {
+	if lvl >= zapcore.DPanicLevel {
+		return true
+	}
+	return lvl >= effectiveLevel(name)
+}
+
+func effectiveLevel(name string) zapcore.Level {
+	overrides := *namedLevels.Load()
+	if len(overrides) > 0 {
+		for n := name; n != ""; {
+			if l, ok := overrides[n]; ok {
+				return l
+			}
+			i := strings.LastIndexByte(n, '.')
+			if i < 0 {
+				break
+			}
+			n = n[:i]
+		}
+	}
+	return Level()
+}
+
+// levelPayload is the body of LevelHandler, an empty Logger targets the global level
+type levelPayload struct {
+	Logger string `json:"logger,omitempty"`
+	Level  string `json:"level"`
+}
+
+type levelState struct {
+	Level   string            `json:"level"`
+	Loggers map[string]string `json:"loggers,omitempty"`
+}
+
+// LevelHandler returns an http.Handler to read and change the levels of a running service.
+// GET returns {"level":"info","loggers":{"billing":"debug"}}, PUT or POST with {"level":"warn"} changes the global level
+// and {"logger":"billing","level":"debug"} overrides a named logger, an empty level removes the override
+func LevelHandler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method {
+		case http.MethodGet:
+		case http.MethodPut, http.MethodPost:
+			var payload levelPayload
+			if err := json.NewDecoder(r.Body).Decode(&payload); err != nil {
+				_ = xerror.WriteProblem(w, xerror.Wrap(err, xerror.ErrCodeInvalidRequest, xerror.WithMessage("invalid level payload")), r.URL.Path)
+				return
+			}
+			if err := applyLevel(payload); err != nil {
+				_ = xerror.WriteProblem(w, err, r.URL.Path)
+				return
+			}
+			Info().Field("logger", payload.Logger).Field("level", payload.Level).Msg("log level changed")
+		default:
+			w.Header().Set("Allow", "GET, PUT, POST")
+			writeMethodNotAllowed(w, r)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(currentLevelState())
+	})
+}
+
+// writeMethodNotAllowed writes a 405 problem, xerror has no code for it so the status of ErrCodeInvalidRequest is replaced
+func writeMethodNotAllowed(w http.ResponseWriter, r *http.Request) {
+	p := xerror.ToProblem(xerror.NewError(xerror.ErrCodeInvalidRequest,
+		xerror.WithMessage(fmt.Sprintf("method %s not allowed", r.Method))), r.URL.Path)
+	p.Status = http.StatusMethodNotAllowed
+	p.Title = http.StatusText(http.StatusMethodNotAllowed)
+	w.Header().Set("Content-Type", xerror.ProblemContentType)
+	w.WriteHeader(p.Status)
+	_ = json.NewEncoder(w).Encode(p)
+}
+
+func applyLevel(payload levelPayload) *xerror.Error {
+	if payload.Logger != "" && payload.Level == "" {
+		ResetNamedLevel(payload.Logger)
+		return nil
+	}
+	l, err := zapcore.ParseLevel(payload.Level)
+	if err != nil || payload.Level == "" {
+		return xerror.Wrap(err, xerror.ErrCodeInvalidRequest, xerror.WithMessage(fmt.Sprintf("invalid log level %s", payload.Level)))
+	}
+	if payload.Logger == "" {
+		SetLevel(l)
+	} else {
+		SetNamedLevel(payload.Logger, l)
+	}
+	return nil
+}
+
+func currentLevelState() levelState {
+	st := levelState{Level: Level().String()}
+	if overrides := NamedLevels(); len(overrides) > 0 {
+		st.Loggers = make(map[string]string, len(overrides))
+		for n, l := range overrides {
+			st.Loggers[n] = l.String()
+		}
+	}
+	return st
+}
@@ -0,0 +1,176 @@
+package xlog
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/kurzgesagtz/xgo/xerror"
+	"go.uber.org/zap/zapcore"
+)
+
+// restoreLevels resets the global level and the named overrides when the test ends
+func restoreLevels(t *testing.T) {
+	t.Helper()
+	original := Level()
+	overrides := NamedLevels()
+	t.Cleanup(func() {
+		SetLevel(original)
+		namedLevels.Store(&overrides)
+	})
+}
+
+func TestSetLevel(t *testing.T) {
+	restoreLevels(t)
+
+	SetLevel(zapcore.WarnLevel)
+	if ev := Info(); ev != nil {
+		t.Error("Expected info event to be disabled")
+	}
+	if ev := Warn(); ev == nil {
+		t.Error("Expected warn event to be enabled")
+	}
+
+	SetLevel(zapcore.FatalLevel)
+	if ev := Panic(); ev == nil {
+		t.Error("Expected panic event to be always enabled")
+	}
+}
+
+func TestDisabledEvent(t *testing.T) {
+	restoreLevels(t)
+	SetLevel(zapcore.ErrorLevel)
+
+	ev := Debug()
+	if ev != nil {
+		t.Fatal("Expected disabled event to be nil")
+	}
+	// chained calls on a disabled event are no-ops
+	ev.Field("key", "value").Err(errors.New("dropped")).Context(t.Context()).Pretty().AddCallerSkip(1).Msg("dropped")
+
+	allocs := testing.AllocsPerRun(100, func() {
+		Debug().Field("key", "value").Msg("dropped")
+	})
+	if allocs != 0 {
+		t.Errorf("Expected no allocation for a disabled event, got %v", allocs)
+	}
+}
+
+func TestNamedLevel(t *testing.T) {
+	restoreLevels(t)
+	SetLevel(zapcore.InfoLevel)
+
+	billing := Named("billing")
+	db := billing.Named("db")
+	if db.Name() != "billing.db" {
+		t.Errorf("Expected billing.db, got %s", db.Name())
+	}
+
+	SetNamedLevel("billing", zapcore.DebugLevel)
+	SetNamedLevel("billing.db", zapcore.ErrorLevel)
+
+	testCases := []struct {
+		name    string
+		logger  *Logger
+		level   zapcore.Level
+		enabled bool
+	}{
+		{"root debug", Named(""), zapcore.DebugLevel, false},
+		{"parent debug", billing, zapcore.DebugLevel, true},
+		{"child warn", db, zapcore.WarnLevel, false},
+		{"grandchild inherits child", db.Named("pool"), zapcore.ErrorLevel, true},
+		{"other logger", Named("billingx"), zapcore.DebugLevel, false},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := Enabled(tc.logger.Name(), tc.level); got != tc.enabled {
+				t.Errorf("Expected enabled %v, got %v", tc.enabled, got)
+			}
+		})
+	}
+
+	ResetNamedLevel("billing.db")
+	if !Enabled("billing.db", zapcore.DebugLevel) {
+		t.Error("Expected billing.db to fall back to billing")
+	}
+	if ev := db.Debug(); ev == nil || ev.name != "billing.db" {
+		t.Errorf("Expected named event, got %+v", ev)
+	}
+}
+
+func TestConfigureResetsLevel(t *testing.T) {
+	restoreLevels(t)
+	original := state.Load()
+	defer state.Store(original)
+
+	if err := Configure(Config{Mode: ModeProduction, OutputPaths: []string{"stdout"}}); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if Level() != zapcore.InfoLevel {
+		t.Errorf("Expected info level in production, got %v", Level())
+	}
+	if ev := Debug(); ev != nil {
+		t.Error("Expected debug event to be disabled in production")
+	}
+
+	if err := Configure(Config{Level: "warn"}); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if Level() != zapcore.WarnLevel {
+		t.Errorf("Expected warn level, got %v", Level())
+	}
+}
+
+func TestLevelHandler(t *testing.T) {
+	restoreLevels(t)
+	SetLevel(zapcore.InfoLevel)
+	handler := LevelHandler()
+
+	do := func(method, body string) (*httptest.ResponseRecorder, levelState) {
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, httptest.NewRequest(method, "/log/level", strings.NewReader(body)))
+		var st levelState
+		if rec.Code == http.StatusOK {
+			if err := json.Unmarshal(rec.Body.Bytes(), &st); err != nil {
+				t.Fatalf("Expected JSON body, got %v", err)
+			}
+		}
+		return rec, st
+	}
+
+	if rec, st := do(http.MethodGet, ""); rec.Code != http.StatusOK || st.Level != "info" {
+		t.Errorf("Expected info, got %d %+v", rec.Code, st)
+	}
+
+	if rec, st := do(http.MethodPut, `{"level":"error"}`); rec.Code != http.StatusOK || st.Level != "error" || Level() != zapcore.ErrorLevel {
+		t.Errorf("Expected error level, got %d %+v", rec.Code, st)
+	}
+
+	rec, st := do(http.MethodPost, `{"logger":"billing","level":"debug"}`)
+	if rec.Code != http.StatusOK || st.Loggers["billing"] != "debug" {
+		t.Errorf("Expected billing override, got %d %+v", rec.Code, st)
+	}
+
+	rec, st = do(http.MethodPut, `{"logger":"billing"}`)
+	if rec.Code != http.StatusOK || len(st.Loggers) != 0 {
+		t.Errorf("Expected override to be removed, got %d %+v", rec.Code, st)
+	}
+
+	for _, body := range []string{`{"level":"loud"}`, `{}`, `not json`} {
+		if rec, _ := do(http.MethodPut, body); rec.Code != http.StatusBadRequest {
+			t.Errorf("Expected bad request for %s, got %d", body, rec.Code)
+		}
+	}
+	if rec, _ := do(http.MethodDelete, ""); rec.Code != http.StatusMethodNotAllowed || rec.Header().Get("Allow") != "GET, PUT, POST" {
+		t.Errorf("Expected unsupported method to be rejected, got %d", rec.Code)
+	} else if p, err := xerror.ParseProblem(rec.Body.Bytes()); err != nil || rec.Header().Get("Content-Type") != xerror.ProblemContentType ||
+		p.Message != "method DELETE not allowed" {
+		t.Errorf("Expected problem details, got %s", rec.Body.String())
+	}
+	if Level() != zapcore.ErrorLevel {
+		t.Errorf("Expected rejected requests to keep the level, got %v", Level())
+	}
+}
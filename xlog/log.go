@@ -1,10 +1,15 @@
 package xlog
 
 import (
+	"context"
+	"fmt"
+	"github.com/kurzgesagtz/xgo/xerror"
 	"go.uber.org/zap"
 	"go.uber.org/zap/zapcore"
 	"os"
+	"sort"
 	"strings"
+	"sync/atomic"
 	"time"
 )
 
@@ -19,64 +24,295 @@ const (
 	ObjDebugPrettyPrint string = "$__pretty_print"
 )
 
-var logger *zap.Logger
-var mode = development
-var appName = "local"
+// Modes of Config
+const (
+	ModeDevelopment = development
+	ModeProduction  = production
+	ModePretty      = pretty
+)
+
+// Config configures the global logger, zero values fall back to the defaults of the mode
+type Config struct {
+	// Mode is ModeDevelopment, ModeProduction or ModePretty, default is ModeDevelopment
+	Mode string
+	// AppName is added to every log as app_name, default is "local"
+	AppName string
+	// Level is the minimum level such as "debug" or "warn", default is debug in development and info in production
+	Level string
+	// OutputPaths are zap sink URLs or file paths, default is stdout. The indented JSON block of pretty mode and
+	// LogEvent.Pretty is always printed to stdout
+	OutputPaths []string
+	// ErrorOutputPaths receive internal errors of the logger, default is stderr
+	ErrorOutputPaths []string
+	// Encoding is "json" or "console", default is json in production and console otherwise
+	Encoding string
+	// Sampling limits the number of logs per second, default is the zap production sampling in production
+	Sampling *zap.SamplingConfig
+	// InitialFields are added to every log
+	InitialFields map[string]any
+	// TimeFormat is a time layout of the timestamp in UTC, default is time.RFC3339Nano
+	TimeFormat string
+	// CallerSkip skips additional frames when the caller is resolved, use it when xlog is wrapped by a helper
+	CallerSkip int
+}
+
+// loggerState is swapped as a whole by Configure, a LogEvent keeps the state it was created with
+type loggerState struct {
+	logger  *zap.Logger
+	mode    string
+	appName string
+	level   zapcore.Level
+	// close closes the sinks opened for logger
+	close func()
+}
+
+var state atomic.Pointer[loggerState]
 
 func init() {
+	if err := Configure(ConfigFromEnv()); err != nil {
+		fmt.Fprintf(os.Stderr, "xlog: invalid configuration from environment, using defaults: %v\n", err)
+		_ = Configure(Config{})
+	}
+}
+
+// ConfigFromEnv reads the mode from LOG_MODE and the app name from APP_NAME
+func ConfigFromEnv() Config {
+	cfg := Config{}
 	if aName, ok := os.LookupEnv(logAppNameKey); ok {
-		appName = aName
+		cfg.AppName = aName
 	}
-	pe := zap.NewDevelopmentConfig()
 	if mdos, ok := os.LookupEnv(logProductionKey); ok {
-		md := strings.ToLower(mdos)
-		if md == production {
-			pe = zap.NewProductionConfig()
-			mode = production
-		} else if md == pretty {
-			mode = pretty
+		cfg.Mode = strings.ToLower(mdos)
+	}
+	return cfg
+}
+
+// Configure builds a logger from cfg and replaces the global logger, the previous one is synced and its sinks are
+// closed so events created before Configure and logged after it are lost. The global level is reset to the level
+// of cfg, named level overrides are kept. In pretty mode, and for LogEvent.Pretty in development, the indented JSON
+// block is printed to stdout whatever the OutputPaths
+func Configure(cfg Config) error {
+	st, err := newLoggerState(cfg)
+	if err != nil {
+		return err
+	}
+	previous := state.Swap(st)
+	SetLevel(st.level)
+	if previous != nil {
+		previous.release()
+	}
+	return nil
+}
+
+// release flushes the buffered entries of the logger and closes its sinks
+func (st *loggerState) release() {
+	_ = st.logger.Sync()
+	if st.close != nil {
+		st.close()
+	}
+}
+
+func newLoggerState(cfg Config) (*loggerState, error) {
+	st := &loggerState{
+		mode:    development,
+		appName: "local",
+	}
+	if cfg.AppName != "" {
+		st.appName = cfg.AppName
+	}
+	pe := zap.NewDevelopmentConfig()
+	st.level = zapcore.DebugLevel
+	switch cfg.Mode {
+	case "", development:
+	case production:
+		pe = zap.NewProductionConfig()
+		st.mode = production
+		st.level = zapcore.InfoLevel
+	case pretty:
+		st.mode = pretty
+	default:
+		return nil, xerror.NewError(xerror.ErrCodeInvalidRequest, xerror.WithMessage(fmt.Sprintf("unsupported log mode %s", cfg.Mode)))
+	}
+	if cfg.Level != "" {
+		level, err := zapcore.ParseLevel(cfg.Level)
+		if err != nil {
+			return nil, xerror.Wrap(err, xerror.ErrCodeInvalidRequest, xerror.WithMessage(fmt.Sprintf("invalid log level %s", cfg.Level)))
 		}
+		st.level = level
 	}
+	// events are filtered by the xlog level before they are built, see Enabled
+	pe.Level = zap.NewAtomicLevelAt(zapcore.DebugLevel)
+
 	pe.EncoderConfig.TimeKey = "timestamp"
+	encodeTime := zapcore.RFC3339NanoTimeEncoder
+	if cfg.TimeFormat != "" {
+		encodeTime = zapcore.TimeEncoderOfLayout(cfg.TimeFormat)
+	}
 	pe.EncoderConfig.EncodeTime = func(t time.Time, enc zapcore.PrimitiveArrayEncoder) {
-		zapcore.RFC3339NanoTimeEncoder(t.UTC(), enc)
+		encodeTime(t.UTC(), enc)
 	}
-
 	if pe.Development {
 		pe.EncoderConfig.EncodeLevel = zapcore.CapitalColorLevelEncoder
 	}
+	if cfg.Encoding != "" {
+		pe.Encoding = cfg.Encoding
+	}
+	if cfg.Sampling != nil {
+		pe.Sampling = cfg.Sampling
+	}
+	pe.InitialFields = cfg.InitialFields
 	pe.OutputPaths = []string{"stdout"}
+	if len(cfg.OutputPaths) > 0 {
+		pe.OutputPaths = cfg.OutputPaths
+	}
+	if len(cfg.ErrorOutputPaths) > 0 {
+		pe.ErrorOutputPaths = cfg.ErrorOutputPaths
+	}
+	logger, closeSinks, err := buildLogger(pe, cfg.CallerSkip)
+	if err != nil {
+		return nil, xerror.Wrap(err, xerror.ErrCodeInvalidRequest, xerror.WithMessage("cannot build logger"))
+	}
+	st.logger = logger
+	st.close = closeSinks
+	return st, nil
+}
+
+// buildLogger is zap.Config.Build which returns the function closing the sinks it opened
+func buildLogger(pe zap.Config, callerSkip int) (*zap.Logger, func(), error) {
+	var enc zapcore.Encoder
+	switch pe.Encoding {
+	case "json":
+		enc = zapcore.NewJSONEncoder(pe.EncoderConfig)
+	case "console":
+		enc = zapcore.NewConsoleEncoder(pe.EncoderConfig)
+	default:
+		return nil, nil, fmt.Errorf("unsupported encoding %s", pe.Encoding)
+	}
+	out, closeOut, err := zap.Open(pe.OutputPaths...)
+	if err != nil {
+		return nil, nil, err
+	}
+	errOut, closeErrOut, err := zap.Open(pe.ErrorOutputPaths...)
+	if err != nil {
+		closeOut()
+		return nil, nil, err
+	}
+
+	core := zapcore.NewCore(enc, out, pe.Level)
+	if pe.Sampling != nil {
+		var samplerOpts []zapcore.SamplerOption
+		if pe.Sampling.Hook != nil {
+			samplerOpts = append(samplerOpts, zapcore.SamplerHook(pe.Sampling.Hook))
+		}
+		core = zapcore.NewSamplerWithOptions(core, time.Second, pe.Sampling.Initial, pe.Sampling.Thereafter, samplerOpts...)
+	}
 	opt := []zap.Option{
+		zap.ErrorOutput(errOut),
 		zap.AddCaller(),
-		zap.AddCallerSkip(1),
+		zap.AddCallerSkip(1 + callerSkip),
 		zap.AddStacktrace(zap.PanicLevel),
 	}
-
-	logger, _ = pe.Build(opt...)
+	if pe.Development {
+		opt = append(opt, zap.Development())
+	}
+	if len(pe.InitialFields) > 0 {
+		keys := make([]string, 0, len(pe.InitialFields))
+		for k := range pe.InitialFields {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		fields := make([]zap.Field, 0, len(keys))
+		for _, k := range keys {
+			fields = append(fields, zap.Any(k, pe.InitialFields[k]))
+		}
+		opt = append(opt, zap.Fields(fields...))
+	}
+	return zap.New(core, opt...), func() {
+		closeOut()
+		closeErrOut()
+	}, nil
 }
 
 func Debug() *LogEvent {
-	return newLogEvent(zapcore.DebugLevel)
+	return newLogEvent("", zapcore.DebugLevel)
 }
 
 func Info() *LogEvent {
-	return newLogEvent(zapcore.InfoLevel)
+	return newLogEvent("", zapcore.InfoLevel)
 }
 
 func Warn() *LogEvent {
-	return newLogEvent(zapcore.WarnLevel)
+	return newLogEvent("", zapcore.WarnLevel)
 }
 
 func Error() *LogEvent {
-	return newLogEvent(zapcore.ErrorLevel)
+	return newLogEvent("", zapcore.ErrorLevel)
 }
 
 func Panic() *LogEvent {
-	return newLogEvent(zapcore.PanicLevel)
+	return newLogEvent("", zapcore.PanicLevel)
 }
 
 func Fatal() *LogEvent {
-	return newLogEvent(zapcore.FatalLevel)
+	return newLogEvent("", zapcore.FatalLevel)
+}
+
+// Logger creates events of a named logger, its level can be overridden with SetNamedLevel.
+// A logger from FromContext gives its context to every event
+type Logger struct {
+	name string
+	ctx  context.Context
+}
+
+// Named returns the logger name, children are separated by a dot such as "billing.db"
+func Named(name string) *Logger {
+	return &Logger{name: name}
+}
+
+// Named returns a child logger of l, it keeps the context of l
+func (l *Logger) Named(name string) *Logger {
+	child := &Logger{name: name, ctx: l.ctx}
+	if l.name != "" {
+		child.name = l.name + "." + name
+	}
+	return child
+}
+
+// Name returns the name of the logger
+func (l *Logger) Name() string {
+	return l.name
+}
+
+func (l *Logger) Debug() *LogEvent {
+	return l.newLogEvent(zapcore.DebugLevel)
+}
+
+func (l *Logger) Info() *LogEvent {
+	return l.newLogEvent(zapcore.InfoLevel)
+}
+
+func (l *Logger) Warn() *LogEvent {
+	return l.newLogEvent(zapcore.WarnLevel)
+}
+
+func (l *Logger) Error() *LogEvent {
+	return l.newLogEvent(zapcore.ErrorLevel)
+}
+
+func (l *Logger) Panic() *LogEvent {
+	return l.newLogEvent(zapcore.PanicLevel)
+}
+
+func (l *Logger) Fatal() *LogEvent {
+	return l.newLogEvent(zapcore.FatalLevel)
+}
+
+func (l *Logger) newLogEvent(level zapcore.Level) *LogEvent {
+	ev := newLogEvent(l.name, level)
+	if l.ctx != nil {
+		ev = ev.Context(l.ctx)
+	}
+	return ev
 }
 
 func PrettyPrint(obj ...any) {
@@ -1,12 +1,22 @@
 package xlog
 
 import (
+	"encoding/json"
 	"os"
+	"net/url"
+	"path/filepath"
+	"strings"
+	"sync"
 	"testing"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
 )
 
 func TestLogLevels(t *testing.T) {
-	// Test that all log level functions return a non-nil LogEvent
+	// Test that all log level functions return a non-nil LogEvent when every level is enabled
+	restoreLevels(t)
+	SetLevel(zapcore.DebugLevel)
 	tests := []struct {
 		name     string
 		logFunc  func() *LogEvent
@@ -51,9 +61,7 @@ func TestLogModeEnvironmentVariable(t *testing.T) {
 	// This test is limited because we can't directly call init()
 	// We can only verify the current mode based on environment variables
 
-	// Save original mode and restore it after the test
-	originalMode := mode
-	defer func() { mode = originalMode }()
+	mode := state.Load().mode
 
 	// Test the current mode based on environment variables
 	logMode, exists := os.LookupEnv(logProductionKey)
@@ -82,9 +90,7 @@ func TestAppNameEnvironmentVariable(t *testing.T) {
 	// This test is limited because we can't directly call init()
 	// We can only verify the current appName based on environment variables
 
-	// Save original app name and restore it after the test
-	originalAppName := appName
-	defer func() { appName = originalAppName }()
+	appName := state.Load().appName
 
 	// Test the current appName based on environment variables
 	envAppName, exists := os.LookupEnv(logAppNameKey)
@@ -119,3 +125,167 @@ func TestPrettyPrint(t *testing.T) {
 	// This should not panic
 	PrettyPrint(obj, obj2)
 }
+
+func TestConfigure(t *testing.T) {
+	original := state.Load()
+	defer state.Store(original)
+	restoreLevels(t)
+
+	path := filepath.Join(t.TempDir(), "app.log")
+	err := Configure(Config{
+		Mode:          ModeProduction,
+		AppName:       "billing",
+		Level:         "warn",
+		OutputPaths:   []string{path},
+		InitialFields: map[string]any{"region": "eu"},
+		TimeFormat:    "2006-01-02",
+	})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	Info().Msg("filtered")
+	Warn().Field("invoice_id", "i-1").Msg("kept")
+	_ = state.Load().logger.Sync()
+
+	b, rErr := os.ReadFile(path)
+	if rErr != nil {
+		t.Fatalf("Expected log file, got %v", rErr)
+	}
+	lines := strings.Split(strings.TrimSpace(string(b)), "\n")
+	if len(lines) != 1 {
+		t.Fatalf("Expected only the warn log, got %q", lines)
+	}
+
+	var entry map[string]any
+	if err := json.Unmarshal([]byte(lines[0]), &entry); err != nil {
+		t.Fatalf("Expected JSON log, got %v", err)
+	}
+	if entry["msg"] != "kept" || entry["app_name"] != "billing" || entry["region"] != "eu" {
+		t.Errorf("Unexpected entry %v", entry)
+	}
+	if ts, _ := entry["timestamp"].(string); len(ts) != len("2006-01-02") {
+		t.Errorf("Expected timestamp with the configured format, got %v", entry["timestamp"])
+	}
+}
+
+// recordingSink records the calls made by the logger to its sink
+type recordingSink struct {
+	mu     sync.Mutex
+	lines  int
+	synced bool
+	closed bool
+}
+
+func (s *recordingSink) Write(p []byte) (int, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.lines++
+	return len(p), nil
+}
+
+func (s *recordingSink) Sync() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.synced = true
+	return nil
+}
+
+func (s *recordingSink) Close() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.closed = true
+	return nil
+}
+
+// recordingSinks maps the host of xlogtest:// sink URLs to their sink, the scheme can only be registered once
+var recordingSinks sync.Map
+
+var registerRecordingSink = sync.OnceValue(func() error {
+	return zap.RegisterSink("xlogtest", func(u *url.URL) (zap.Sink, error) {
+		sink, _ := recordingSinks.LoadOrStore(u.Host, &recordingSink{})
+		return sink.(*recordingSink), nil
+	})
+})
+
+func TestConfigure_ReleasesPrevious(t *testing.T) {
+	original := state.Load()
+	defer state.Store(original)
+	restoreLevels(t)
+
+	if err := registerRecordingSink(); err != nil {
+		t.Fatalf("Expected sink to be registered, got %v", err)
+	}
+	sink := &recordingSink{}
+	recordingSinks.Store(t.Name(), sink)
+	defer recordingSinks.Delete(t.Name())
+	if err := Configure(Config{Mode: ModeProduction, OutputPaths: []string{"xlogtest://" + t.Name()}}); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	Info().Msg("before reconfigure")
+
+	if err := Configure(Config{Mode: ModeProduction, OutputPaths: []string{filepath.Join(t.TempDir(), "app.log")}}); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	sink.mu.Lock()
+	defer sink.mu.Unlock()
+	if sink.lines != 1 || !sink.synced || !sink.closed {
+		t.Errorf("Expected the previous sink to be synced and closed, got %+v", sink)
+	}
+}
+
+func TestConfigure_Invalid(t *testing.T) {
+	original := state.Load()
+	defer state.Store(original)
+	restoreLevels(t)
+
+	testCases := []struct {
+		name string
+		cfg  Config
+	}{
+		{"mode", Config{Mode: "verbose"}},
+		{"level", Config{Level: "loud"}},
+		{"output", Config{OutputPaths: []string{"unknown-scheme://log"}}},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			if err := Configure(tc.cfg); err == nil {
+				t.Error("Expected error")
+			}
+			if state.Load() != original {
+				t.Error("Expected the logger not to be replaced on error")
+			}
+		})
+	}
+}
+
+func TestConfigure_Concurrent(t *testing.T) {
+	original := state.Load()
+	defer state.Store(original)
+	restoreLevels(t)
+
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(2)
+		go func() {
+			defer wg.Done()
+			_ = Configure(Config{Mode: ModeProduction, OutputPaths: []string{os.DevNull}})
+		}()
+		go func() {
+			defer wg.Done()
+			Info().Field("i", 1).Msg("concurrent")
+		}()
+	}
+	wg.Wait()
+}
+
+func TestConfigFromEnv(t *testing.T) {
+	t.Setenv(logProductionKey, "PRODUCTION")
+	t.Setenv(logAppNameKey, "env-app")
+
+	cfg := ConfigFromEnv()
+	if cfg.Mode != production || cfg.AppName != "env-app" {
+		t.Errorf("Unexpected config %+v", cfg)
+	}
+}
@@ -0,0 +1,38 @@
+//go:build !windows
+
+package xlog
+
+import (
+	"context"
+	"os"
+	"os/signal"
+	"syscall"
+
+	"go.uber.org/zap/zapcore"
+)
+
+// WatchLevelSignals changes the global level of a running service until ctx is done, SIGUSR1 lowers the level one step
+// to log more and SIGUSR2 raises it one step to log less, the level stays between debug and error
+func WatchLevelSignals(ctx context.Context) {
+	ch := make(chan os.Signal, 1)
+	signal.Notify(ch, syscall.SIGUSR1, syscall.SIGUSR2)
+	go func() {
+		defer signal.Stop(ch)
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case sig := <-ch:
+				l := Level()
+				if sig == syscall.SIGUSR1 {
+					l = max(l-1, zapcore.DebugLevel)
+				} else {
+					l = min(l+1, zapcore.ErrorLevel)
+				}
+				SetLevel(l)
+				// the confirmation bypasses the level, it would be dropped once SIGUSR2 raises the level above warn
+				buildLogEvent("", zapcore.WarnLevel).Field("level", l.String()).Field("signal", sig.String()).Msg("log level changed")
+			}
+		}
+	}()
+}
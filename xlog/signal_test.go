@@ -0,0 +1,57 @@
+//go:build !windows
+
+package xlog
+
+import (
+	"context"
+	"syscall"
+	"testing"
+	"time"
+
+	"go.uber.org/zap/zapcore"
+)
+
+func TestWatchLevelSignals(t *testing.T) {
+	read := captureLogs(t)
+	SetLevel(zapcore.InfoLevel)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	WatchLevelSignals(ctx)
+
+	testCases := []struct {
+		sig      syscall.Signal
+		expected zapcore.Level
+	}{
+		{syscall.SIGUSR1, zapcore.DebugLevel},
+		{syscall.SIGUSR2, zapcore.InfoLevel},
+		{syscall.SIGUSR2, zapcore.WarnLevel},
+		{syscall.SIGUSR2, zapcore.ErrorLevel},
+	}
+	for _, tc := range testCases {
+		if err := syscall.Kill(syscall.Getpid(), tc.sig); err != nil {
+			t.Fatalf("Expected signal to be sent, got %v", err)
+		}
+		deadline := time.Now().Add(time.Second)
+		for Level() != tc.expected && time.Now().Before(deadline) {
+			time.Sleep(time.Millisecond)
+		}
+		if Level() != tc.expected {
+			t.Fatalf("Expected %v after %v, got %v", tc.expected, tc.sig, Level())
+		}
+	}
+
+	// The confirmation is logged even when the new level is above warn
+	deadline := time.Now().Add(time.Second)
+	entries := read()
+	for len(entries) < len(testCases) && time.Now().Before(deadline) {
+		time.Sleep(time.Millisecond)
+		entries = read()
+	}
+	if len(entries) != len(testCases) {
+		t.Fatalf("Expected %d level changes to be logged, got %v", len(testCases), entries)
+	}
+	if last := entries[len(entries)-1]; last["msg"] != "log level changed" {
+		t.Errorf("Expected the change to error to be logged, got %v", last)
+	}
+}
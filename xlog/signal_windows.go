@@ -0,0 +1,6 @@
+package xlog
+
+import "context"
+
+// WatchLevelSignals does nothing on windows which has no SIGUSR1 and SIGUSR2, use LevelHandler instead
+func WatchLevelSignals(ctx context.Context) {}
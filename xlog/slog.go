@@ -0,0 +1,158 @@
+package xlog
+
+import (
+	"context"
+	"log/slog"
+	"runtime"
+	"slices"
+
+	"go.uber.org/zap/zapcore"
+)
+
+// SlogHandler is a slog.Handler writing records as xlog events, so libraries logging with log/slog get the app name,
+// the trace ids of the context, the level of xlog and the pretty mode. Entries keep the time of the record. Attributes go to the data field, groups are nested
+// maps and the first error attribute outside of a group is logged with LogEvent.Err
+type SlogHandler struct {
+	name   string
+	groups []string
+	attrs  []slogAttr
+}
+
+// slogAttr is an attribute added with WithAttrs, it keeps the groups opened before it
+type slogAttr struct {
+	groups []string
+	attr   slog.Attr
+}
+
+// NewSlogHandler returns a handler logging to the logger name, an empty name is the root logger
+func NewSlogHandler(name string) *SlogHandler {
+	return &SlogHandler{name: name}
+}
+
+// SetSlogDefault makes slog.Default, and the standard log package, write to the logger name
+func SetSlogDefault(name string) {
+	slog.SetDefault(slog.New(NewSlogHandler(name)))
+}
+
+// Enabled reports whether the xlog level of the logger accepts level
+func (h *SlogHandler) Enabled(_ context.Context, level slog.Level) bool {
+	return Enabled(h.name, slogLevel(level))
+}
+
+func (h *SlogHandler) Handle(ctx context.Context, r slog.Record) error {
+	ev := newLogEvent(h.name, slogLevel(r.Level))
+	if ev == nil {
+		return nil
+	}
+	if r.PC != 0 {
+		ev.frame, _ = runtime.CallersFrames([]uintptr{r.PC}).Next()
+	}
+	// a zero time is omitted as slog.Handler requires
+	ev.timestamp, ev.hasTimestamp = r.Time, true
+	ev = ev.Context(ctx)
+
+	data := make(map[string]any)
+	keys := make([]string, 0, len(h.attrs)+r.NumAttrs())
+	add := func(groups []string, a slog.Attr) {
+		a.Value = a.Value.Resolve()
+		if len(groups) == 0 && ev.err == nil {
+			if err, ok := a.Value.Any().(error); ok && a.Value.Kind() == slog.KindAny {
+				ev.Err(err)
+				return
+			}
+		}
+		addSlogAttr(data, groups, a, func(key string) {
+			if !slices.Contains(keys, key) {
+				keys = append(keys, key)
+			}
+		})
+	}
+	for _, a := range h.attrs {
+		add(a.groups, a.attr)
+	}
+	r.Attrs(func(a slog.Attr) bool {
+		add(h.groups, a)
+		return true
+	})
+	for _, key := range keys {
+		ev.Field(key, data[key])
+	}
+	ev.Msg(r.Message)
+	return nil
+}
+
+func (h *SlogHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	if len(attrs) == 0 {
+		return h
+	}
+	h2 := *h
+	h2.attrs = slices.Clip(h.attrs)
+	for _, a := range attrs {
+		h2.attrs = append(h2.attrs, slogAttr{groups: h.groups, attr: a})
+	}
+	return &h2
+}
+
+func (h *SlogHandler) WithGroup(name string) slog.Handler {
+	if name == "" {
+		return h
+	}
+	h2 := *h
+	h2.groups = append(slices.Clip(h.groups), name)
+	return &h2
+}
+
+// addSlogAttr sets a under groups in m following the slog.Handler rules, top is called with the top level keys it sets
+func addSlogAttr(m map[string]any, groups []string, a slog.Attr, top func(string)) {
+	a.Value = a.Value.Resolve()
+	if a.Equal(slog.Attr{}) {
+		return
+	}
+	if a.Value.Kind() == slog.KindGroup {
+		attrs := a.Value.Group()
+		if a.Key != "" {
+			groups = append(slices.Clip(groups), a.Key)
+		}
+		for _, ga := range attrs {
+			addSlogAttr(m, groups, ga, top)
+		}
+		return
+	}
+	if len(groups) == 0 {
+		m[a.Key] = slogValue(a.Value)
+		top(a.Key)
+		return
+	}
+	current := m
+	for _, g := range groups {
+		child, ok := current[g].(map[string]any)
+		if !ok {
+			child = make(map[string]any)
+			current[g] = child
+		}
+		current = child
+	}
+	current[a.Key] = slogValue(a.Value)
+	top(groups[0])
+}
+
+func slogValue(v slog.Value) any {
+	if err, ok := v.Any().(error); ok && v.Kind() == slog.KindAny {
+		return err.Error()
+	}
+	return v.Any()
+}
+
+// slogLevel maps a slog level to the closest xlog level, levels above error stay error
+func slogLevel(level slog.Level) zapcore.Level {
+	switch {
+	case level < slog.LevelInfo:
+		return zapcore.DebugLevel
+	case level < slog.LevelWarn:
+		return zapcore.InfoLevel
+	case level < slog.LevelError:
+		return zapcore.WarnLevel
+	default:
+		return zapcore.ErrorLevel
+	}
+}
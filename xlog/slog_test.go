@@ -0,0 +1,177 @@
+package xlog
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"testing/slogtest"
+	"time"
+
+	"go.uber.org/zap/zapcore"
+)
+
+// captureLogs configures a JSON logger writing to a file and returns a function reading its entries
+func captureLogs(t *testing.T) func() []map[string]any {
+	t.Helper()
+	original := state.Load()
+	t.Cleanup(func() { state.Store(original) })
+	restoreLevels(t)
+
+	path := filepath.Join(t.TempDir(), "app.log")
+	if err := Configure(Config{Mode: ModeProduction, Level: "debug", OutputPaths: []string{path}}); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	return func() []map[string]any {
+		_ = state.Load().logger.Sync()
+		b, err := os.ReadFile(path)
+		if err != nil {
+			t.Fatalf("Expected log file, got %v", err)
+		}
+		entries := make([]map[string]any, 0)
+		for _, line := range strings.Split(strings.TrimSpace(string(b)), "\n") {
+			if line == "" {
+				continue
+			}
+			var entry map[string]any
+			if err := json.Unmarshal([]byte(line), &entry); err != nil {
+				t.Fatalf("Expected JSON log, got %v", err)
+			}
+			entries = append(entries, entry)
+		}
+		return entries
+	}
+}
+
+func TestSlogHandler(t *testing.T) {
+	read := captureLogs(t)
+	logger := slog.New(NewSlogHandler("lib")).With("component", "client").WithGroup("req")
+
+	ctx := WithFields(context.Background(), "tenant_id", "acme")
+	logger.InfoContext(ctx, "request sent", "method", "GET", slog.Group("retry", "attempt", 2), slog.Group("empty"))
+	slog.New(NewSlogHandler("")).Error("request failed", "err", errors.New("connection refused"), "status", 502)
+
+	entries := read()
+	if len(entries) != 2 {
+		t.Fatalf("Expected 2 entries, got %v", entries)
+	}
+
+	entry := entries[0]
+	if entry["msg"] != "request sent" || entry["level"] != "info" || entry["logger"] != "lib" || entry["tenant_id"] != "acme" {
+		t.Errorf("Unexpected entry %v", entry)
+	}
+	if caller, _ := entry["caller"].(string); !strings.HasPrefix(caller, "xlog/slog_test.go:") {
+		t.Errorf("Expected caller of the slog call, got %v", entry["caller"])
+	}
+	data, _ := entry["data"].(map[string]any)
+	req, _ := data["req"].(map[string]any)
+	retry, _ := req["retry"].(map[string]any)
+	if data["component"] != "client" || req["method"] != "GET" || retry["attempt"] != float64(2) {
+		t.Errorf("Expected grouped attributes, got %v", data)
+	}
+	if _, ok := req["empty"]; ok {
+		t.Errorf("Expected empty group to be omitted, got %v", req)
+	}
+
+	entry = entries[1]
+	data, _ = entry["data"].(map[string]any)
+	if entry["level"] != "error" || entry["error"] != "connection refused" || data["status"] != float64(502) {
+		t.Errorf("Unexpected entry %v", entry)
+	}
+	if _, ok := data["err"]; ok {
+		t.Errorf("Expected error attribute to be logged with Err, got %v", data)
+	}
+}
+
+func TestSlogHandler_Conformance(t *testing.T) {
+	var read func() []map[string]any
+	newHandler := func(t *testing.T) slog.Handler {
+		read = captureLogs(t)
+		return NewSlogHandler("")
+	}
+	// result maps the entry to the slog layout, the attributes are in data and the time in timestamp
+	result := func(t *testing.T) map[string]any {
+		entries := read()
+		if len(entries) != 1 {
+			t.Fatalf("Expected 1 entry, got %v", entries)
+		}
+		entry := entries[0]
+		m := map[string]any{slog.MessageKey: entry["msg"], slog.LevelKey: entry["level"]}
+		if ts, ok := entry["timestamp"].(string); ok {
+			tm, err := time.Parse(time.RFC3339Nano, ts)
+			if err != nil {
+				t.Fatalf("Expected RFC3339 timestamp, got %v", ts)
+			}
+			m[slog.TimeKey] = tm
+		}
+		data, _ := entry["data"].(map[string]any)
+		for k, v := range data {
+			m[k] = v
+		}
+		return m
+	}
+	slogtest.Run(t, newHandler, result)
+}
+
+func TestSlogHandler_Time(t *testing.T) {
+	read := captureLogs(t)
+	at := time.Date(2024, 5, 1, 10, 30, 0, 0, time.UTC)
+	h := NewSlogHandler("")
+	_ = h.Handle(context.Background(), slog.NewRecord(at, slog.LevelInfo, "explicit time", 0))
+	_ = h.Handle(context.Background(), slog.NewRecord(time.Time{}, slog.LevelInfo, "zero time", 0))
+
+	entries := read()
+	if len(entries) != 2 {
+		t.Fatalf("Expected 2 entries, got %v", entries)
+	}
+	if entries[0]["timestamp"] != at.Format(time.RFC3339Nano) {
+		t.Errorf("Expected the time of the record, got %v", entries[0]["timestamp"])
+	}
+	if _, ok := entries[1]["timestamp"]; ok {
+		t.Errorf("Expected zero time to be omitted, got %v", entries[1]["timestamp"])
+	}
+}
+
+func TestSlogHandler_Enabled(t *testing.T) {
+	restoreLevels(t)
+	SetLevel(zapcore.WarnLevel)
+	h := NewSlogHandler("lib")
+
+	testCases := []struct {
+		level   slog.Level
+		enabled bool
+	}{
+		{slog.LevelDebug, false},
+		{slog.LevelInfo, false},
+		{slog.LevelWarn, true},
+		{slog.LevelError + 4, true},
+	}
+	for _, tc := range testCases {
+		if got := h.Enabled(context.Background(), tc.level); got != tc.enabled {
+			t.Errorf("Expected enabled %v for %v, got %v", tc.enabled, tc.level, got)
+		}
+	}
+
+	SetNamedLevel("lib", zapcore.DebugLevel)
+	if !h.Enabled(context.Background(), slog.LevelDebug) {
+		t.Error("Expected named level override to apply")
+	}
+}
+
+func TestSetSlogDefault(t *testing.T) {
+	read := captureLogs(t)
+	original := slog.Default()
+	defer slog.SetDefault(original)
+
+	SetSlogDefault("std")
+	slog.Warn("from slog", "key", "value")
+
+	entries := read()
+	if len(entries) != 1 || entries[0]["msg"] != "from slog" || entries[0]["logger"] != "std" {
+		t.Errorf("Unexpected entries %v", entries)
+	}
+}
@@ -0,0 +1,150 @@
+package xretry
+
+import (
+	"context"
+	"errors"
+	"math/rand/v2"
+	"time"
+
+	"github.com/kurzgesagtz/xgo/xerror"
+	"github.com/kurzgesagtz/xgo/xlog"
+)
+
+// Classifier reports whether err is worth another attempt
+type Classifier func(err error) bool
+
+// Policy controls how an operation is retried, the delay of attempt n is
+// InitialDelay * Multiplier^(n-1) capped by MaxDelay, then spread by Jitter
+type Policy struct {
+	// MaxAttempts includes the first attempt
+	MaxAttempts  int
+	InitialDelay time.Duration
+	MaxDelay     time.Duration
+	Multiplier   float64
+	// Jitter is the fraction of the delay which is randomized, 0.2 waits between 80% and 120% of the delay
+	Jitter     float64
+	Classifier Classifier
+}
+
+type PolicyOptionFunc func(p *Policy) *Policy
+
+// DefaultClassifier retries errors whose xerror code is registered as retryable, context cancellation is never retried
+func DefaultClassifier(err error) bool {
+	if err == nil || errors.Is(err, context.Canceled) {
+		return false
+	}
+	return xerror.IsRetryable(xerror.GetErrorCode(err))
+}
+
+// DefaultPolicy makes 3 attempts starting with 100ms delay doubled up to 10s with 20% jitter
+func DefaultPolicy() *Policy {
+	return &Policy{
+		MaxAttempts:  3,
+		InitialDelay: 100 * time.Millisecond,
+		MaxDelay:     10 * time.Second,
+		Multiplier:   2,
+		Jitter:       0.2,
+		Classifier:   DefaultClassifier,
+	}
+}
+
+func WithMaxAttempts(n int) PolicyOptionFunc {
+	return func(p *Policy) *Policy {
+		p.MaxAttempts = n
+		return p
+	}
+}
+
+func WithBackoff(initial, max time.Duration, multiplier float64) PolicyOptionFunc {
+	return func(p *Policy) *Policy {
+		p.InitialDelay = initial
+		p.MaxDelay = max
+		p.Multiplier = multiplier
+		return p
+	}
+}
+
+func WithJitter(jitter float64) PolicyOptionFunc {
+	return func(p *Policy) *Policy {
+		p.Jitter = jitter
+		return p
+	}
+}
+
+// WithClassifier overrides DefaultClassifier
+func WithClassifier(c Classifier) PolicyOptionFunc {
+	return func(p *Policy) *Policy {
+		p.Classifier = c
+		return p
+	}
+}
+
+// Delay returns the wait before the attempt following attempt, the retry after value of err takes precedence over the backoff.
+// MaxDelay caps both since the retry after value comes from the peer
+func (p *Policy) Delay(attempt int, err error) time.Duration {
+	if d, ok := xerror.RetryAfter(err); ok {
+		if p.MaxDelay > 0 && d > p.MaxDelay {
+			return p.MaxDelay
+		}
+		return d
+	}
+	delay := float64(p.InitialDelay)
+	for i := 1; i < attempt; i++ {
+		delay *= p.Multiplier
+		if p.MaxDelay > 0 && delay >= float64(p.MaxDelay) {
+			break
+		}
+	}
+	if p.MaxDelay > 0 && delay > float64(p.MaxDelay) {
+		delay = float64(p.MaxDelay)
+	}
+	if p.Jitter > 0 {
+		delay += delay * p.Jitter * (2*rand.Float64() - 1)
+	}
+	return time.Duration(delay)
+}
+
+// Do calls fn until it succeeds, returns an error which is not retryable or the attempts are exhausted.
+// The last error of fn is returned when ctx is done or its deadline is too close for the next attempt
+func Do(ctx context.Context, fn func(ctx context.Context) error, opts ...PolicyOptionFunc) error {
+	_, err := DoValue(ctx, func(ctx context.Context) (struct{}, error) {
+		return struct{}{}, fn(ctx)
+	}, opts...)
+	return err
+}
+
+// DoValue is Do for functions returning a value
+func DoValue[T any](ctx context.Context, fn func(ctx context.Context) (T, error), opts ...PolicyOptionFunc) (T, error) {
+	p := DefaultPolicy()
+	for _, opt := range opts {
+		p = opt(p)
+	}
+	classify := p.Classifier
+	if classify == nil {
+		classify = DefaultClassifier
+	}
+
+	for attempt := 1; ; attempt++ {
+		v, err := fn(ctx)
+		if err == nil || attempt >= p.MaxAttempts || !classify(err) {
+			return v, err
+		}
+		delay := p.Delay(attempt, err)
+		if deadline, ok := ctx.Deadline(); ok && time.Until(deadline) < delay {
+			return v, err
+		}
+		xlog.Warn().Context(ctx).Err(err).
+			Field("attempt", attempt).
+			Field("max_attempts", p.MaxAttempts).
+			Field("delay", delay.String()).
+			Msg("retrying")
+
+		timer := time.NewTimer(delay)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return v, err
+		case <-timer.C:
+		}
+	}
+}
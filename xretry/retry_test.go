@@ -0,0 +1,210 @@
+package xretry
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/kurzgesagtz/xgo/xerror"
+)
+
+func fastBackoff() PolicyOptionFunc {
+	return WithBackoff(time.Millisecond, 5*time.Millisecond, 2)
+}
+
+func TestDefaultClassifier(t *testing.T) {
+	testCases := []struct {
+		name   string
+		err    error
+		expect bool
+	}{
+		{"nil", nil, false},
+		{"rate limit", xerror.NewError(xerror.ErrCodeRateLimitExceeded), true},
+		{"partner internal error", xerror.NewError(xerror.ErrCodePartnerInternalError), true},
+		{"deadline", xerror.NewError(xerror.ErrCodeClientRequestDeadlineExceed), true},
+		{"not found", xerror.NewError(xerror.ErrCodeNotFound), false},
+		{"wrapped retryable", xerror.Wrap(errors.New("EOF"), xerror.ErrCodeRateLimitExceeded), true},
+		{"standard error", errors.New("boom"), false},
+		{"canceled", xerror.Wrap(context.Canceled, xerror.ErrCodeRateLimitExceeded), false},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := DefaultClassifier(tc.err); got != tc.expect {
+				t.Errorf("Expected %v, got %v", tc.expect, got)
+			}
+		})
+	}
+}
+
+func TestPolicy_Delay(t *testing.T) {
+	p := DefaultPolicy()
+	p.Jitter = 0
+
+	expected := []time.Duration{100 * time.Millisecond, 200 * time.Millisecond, 400 * time.Millisecond}
+	for i, d := range expected {
+		if got := p.Delay(i+1, nil); got != d {
+			t.Errorf("Expected delay %v for attempt %d, got %v", d, i+1, got)
+		}
+	}
+
+	// Capped by MaxDelay
+	if got := p.Delay(20, nil); got != p.MaxDelay {
+		t.Errorf("Expected delay capped to %v, got %v", p.MaxDelay, got)
+	}
+
+	// Jitter stays within bounds
+	p.Jitter = 0.5
+	for i := 0; i < 100; i++ {
+		if got := p.Delay(1, nil); got < 50*time.Millisecond || got > 150*time.Millisecond {
+			t.Fatalf("Expected delay within 50%% jitter, got %v", got)
+		}
+	}
+
+	// Retry after takes precedence
+	err := xerror.NewError(xerror.ErrCodeRateLimitExceeded, xerror.WithRetryAfter(3*time.Second))
+	if got := p.Delay(1, err); got != 3*time.Second {
+		t.Errorf("Expected retry after delay 3s, got %v", got)
+	}
+
+	// A retry after longer than MaxDelay is capped
+	err = xerror.NewError(xerror.ErrCodeRateLimitExceeded, xerror.WithRetryAfter(time.Hour))
+	if got := p.Delay(1, err); got != p.MaxDelay {
+		t.Errorf("Expected retry after capped to %v, got %v", p.MaxDelay, got)
+	}
+}
+
+func TestDo(t *testing.T) {
+	retryable := xerror.NewError(xerror.ErrCodeRateLimitExceeded)
+
+	testCases := []struct {
+		name          string
+		failures      int
+		err           error
+		opts          []PolicyOptionFunc
+		expectCalls   int
+		expectSuccess bool
+	}{
+		{
+			name:          "success first attempt",
+			expectCalls:   1,
+			expectSuccess: true,
+		},
+		{
+			name:          "success after retries",
+			failures:      2,
+			err:           retryable,
+			expectCalls:   3,
+			expectSuccess: true,
+		},
+		{
+			name:        "attempts exhausted",
+			failures:    5,
+			err:         retryable,
+			expectCalls: 3,
+		},
+		{
+			name:        "not retryable",
+			failures:    5,
+			err:         xerror.NewError(xerror.ErrCodeNotFound),
+			expectCalls: 1,
+		},
+		{
+			name:     "custom classifier",
+			failures: 5,
+			err:      xerror.NewError(xerror.ErrCodeNotFound),
+			opts: []PolicyOptionFunc{
+				WithMaxAttempts(4),
+				WithClassifier(func(err error) bool {
+					return xerror.IsErrorCode(err, xerror.ErrCodeNotFound)
+				}),
+			},
+			expectCalls: 4,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			calls := 0
+			err := Do(context.Background(), func(ctx context.Context) error {
+				calls++
+				if calls <= tc.failures {
+					return tc.err
+				}
+				return nil
+			}, append([]PolicyOptionFunc{fastBackoff()}, tc.opts...)...)
+
+			if calls != tc.expectCalls {
+				t.Errorf("Expected %d calls, got %d", tc.expectCalls, calls)
+			}
+			if tc.expectSuccess && err != nil {
+				t.Errorf("Expected no error, got %v", err)
+			}
+			if !tc.expectSuccess && err != tc.err {
+				t.Errorf("Expected the last error, got %v", err)
+			}
+		})
+	}
+}
+
+func TestDo_ContextDeadline(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	// The retry after is longer than the deadline, the error is returned without waiting
+	err := xerror.NewError(xerror.ErrCodeRateLimitExceeded, xerror.WithRetryAfter(time.Minute))
+	calls := 0
+	start := time.Now()
+	result := Do(ctx, func(ctx context.Context) error {
+		calls++
+		return err
+	})
+
+	if result != err {
+		t.Errorf("Expected the last error, got %v", result)
+	}
+	if calls != 1 {
+		t.Errorf("Expected 1 call, got %d", calls)
+	}
+	if time.Since(start) > 40*time.Millisecond {
+		t.Errorf("Expected no wait, took %v", time.Since(start))
+	}
+}
+
+func TestDo_ContextCanceled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	err := xerror.NewError(xerror.ErrCodeRateLimitExceeded)
+
+	calls := 0
+	result := Do(ctx, func(ctx context.Context) error {
+		calls++
+		cancel()
+		return err
+	}, WithBackoff(time.Second, time.Second, 1))
+
+	if result != err {
+		t.Errorf("Expected the last error, got %v", result)
+	}
+	if calls != 1 {
+		t.Errorf("Expected 1 call, got %d", calls)
+	}
+}
+
+func TestDoValue(t *testing.T) {
+	calls := 0
+	v, err := DoValue(context.Background(), func(ctx context.Context) (string, error) {
+		calls++
+		if calls == 1 {
+			return "", xerror.NewError(xerror.ErrCodePartnerInternalError)
+		}
+		return "ok", nil
+	}, fastBackoff())
+
+	if err != nil || v != "ok" {
+		t.Errorf("Expected ok, got %q %v", v, err)
+	}
+	if calls != 2 {
+		t.Errorf("Expected 2 calls, got %d", calls)
+	}
+}
@@ -61,6 +61,9 @@ func MapToSliceAsync[I any, O any](ctx context.Context, n int, process func(ctx
 		return []O{}, nil
 	}
 
+	// A panic in one worker is returned as error instead of crashing the service
+	process = SafeProcess(process)
+
 	// Use a smaller number of workers if there are fewer values than requested workers
 	if n > valuesSize {
 		n = valuesSize
@@ -0,0 +1,49 @@
+package xutil
+
+import (
+	"context"
+
+	"github.com/kurzgesagtz/xgo/xerror"
+	"github.com/kurzgesagtz/xgo/xlog"
+)
+
+// Recover converts a panic to xerror.ErrCodeServerPanic stored in errp, it must be deferred directly
+//
+//	func work() (err error) {
+//		defer xutil.Recover(&err)
+//		...
+//	}
+func Recover(errp *error) {
+	if r := recover(); r != nil {
+		*errp = xerror.NewPanicError(r)
+	}
+}
+
+// RecoverReport is Recover which also logs the panic with xlog at error level, errp can be nil
+func RecoverReport(ctx context.Context, errp *error) {
+	if r := recover(); r != nil {
+		err := xerror.NewPanicError(r)
+		xlog.Error().Context(ctx).Err(err).Msg("panic recovered")
+		if errp != nil {
+			*errp = err
+		}
+	}
+}
+
+// Go runs fn in a goroutine, a panic or an error of fn is logged instead of crashing the service
+func Go(ctx context.Context, fn func(ctx context.Context) error) {
+	go func() {
+		defer RecoverReport(ctx, nil)
+		if err := fn(ctx); err != nil {
+			xlog.Error().Context(ctx).Err(err).Msg("goroutine failed")
+		}
+	}()
+}
+
+// SafeProcess wraps process so a panic is returned as xerror.ErrCodeServerPanic, MapToSliceAsync workers use it
+func SafeProcess[I, O any](process func(ctx context.Context, value I) (O, error)) func(ctx context.Context, value I) (O, error) {
+	return func(ctx context.Context, value I) (o O, err error) {
+		defer Recover(&err)
+		return process(ctx, value)
+	}
+}
@@ -0,0 +1,104 @@
+package xutil
+
+import (
+	"context"
+	"errors"
+	"github.com/kurzgesagtz/xgo/xerror"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestRecover(t *testing.T) {
+	work := func() (err error) {
+		defer Recover(&err)
+		panic("boom")
+	}
+
+	err := work()
+	if !xerror.IsErrorCode(err, xerror.ErrCodeServerPanic) {
+		t.Fatalf("Expected %s, got %v", xerror.ErrCodeServerPanic, err)
+	}
+
+	var xErr *xerror.Error
+	errors.As(err, &xErr)
+	if xErr.Info["panic"] != "boom" {
+		t.Errorf("Expected panic value in info, got %v", xErr.Info["panic"])
+	}
+
+	if !strings.Contains(xErr.Caller, "recover_test.go") {
+		t.Errorf("Expected caller at the panic, got %s", xErr.Caller)
+	}
+
+	// No panic keeps the error
+	noPanic := func() (err error) {
+		defer Recover(&err)
+		return nil
+	}
+	if err := noPanic(); err != nil {
+		t.Errorf("Expected no error, got %v", err)
+	}
+}
+
+func TestRecoverReport(t *testing.T) {
+	work := func() (err error) {
+		defer RecoverReport(context.Background(), &err)
+		var m map[string]int
+		m["a"] = 1
+		return nil
+	}
+
+	if err := work(); !xerror.IsErrorCode(err, xerror.ErrCodeServerPanic) {
+		t.Errorf("Expected %s, got %v", xerror.ErrCodeServerPanic, err)
+	}
+
+	// nil errp only reports
+	func() {
+		defer RecoverReport(context.Background(), nil)
+		panic("boom")
+	}()
+}
+
+func TestGo(t *testing.T) {
+	done := make(chan struct{})
+	Go(context.Background(), func(ctx context.Context) error {
+		defer close(done)
+		panic("boom")
+	})
+
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("Expected the goroutine to run")
+	}
+}
+
+func TestSafeProcess(t *testing.T) {
+	process := SafeProcess(func(ctx context.Context, v int) (int, error) {
+		if v == 0 {
+			panic("division by zero")
+		}
+		return 10 / v, nil
+	})
+
+	if v, err := process(context.Background(), 2); err != nil || v != 5 {
+		t.Errorf("Expected 5, got %d %v", v, err)
+	}
+
+	if _, err := process(context.Background(), 0); !xerror.IsErrorCode(err, xerror.ErrCodeServerPanic) {
+		t.Errorf("Expected %s, got %v", xerror.ErrCodeServerPanic, err)
+	}
+}
+
+func TestMapToSliceAsync_Panic(t *testing.T) {
+	_, err := MapToSliceAsync(context.Background(), 2, func(ctx context.Context, v int) (int, error) {
+		if v == 3 {
+			panic("boom")
+		}
+		return v, nil
+	}, []int{1, 2, 3, 4})
+
+	if !xerror.IsErrorCode(err, xerror.ErrCodeServerPanic) {
+		t.Errorf("Expected %s, got %v", xerror.ErrCodeServerPanic, err)
+	}
+}